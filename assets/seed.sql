@@ -7,6 +7,14 @@ CREATE TABLE if not exists "resource" (
 	PRIMARY KEY("id" AUTOINCREMENT)
 );
 
+CREATE TABLE IF NOT EXISTS "entity" (
+	"id"	INTEGER NOT NULL UNIQUE,
+	"name"	TEXT NOT NULL UNIQUE,
+	"fields"	TEXT NOT NULL,
+  CONSTRAINT name_fields UNIQUE(name, fields) ON CONFLICT IGNORE,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+
 CREATE TABLE IF NOT EXISTS "locale" (
 	"id" INTEGER NOT NULL UNIQUE,
 	"name" TEXT NOT NULL UNIQUE,
@@ -22,6 +30,16 @@ CREATE TABLE IF NOT EXISTS "person_prop" (
 	PRIMARY KEY("id" AUTOINCREMENT)
 );
 
+CREATE TABLE IF NOT EXISTS "location_prop" (
+	"id"	INTEGER NOT NULL UNIQUE,
+	"locale_id" INTEGER NOT NULL,
+	"type"	TEXT NOT NULL,
+	"value"	TEXT,
+	"code"	TEXT,
+  CONSTRAINT locale_type_value UNIQUE(locale_id, type, value) ON CONFLICT IGNORE,
+	PRIMARY KEY("id" AUTOINCREMENT)
+);
+
 CREATE TABLE IF NOT EXISTS "misc_prop" (
 	"id"	INTEGER NOT NULL UNIQUE,
 	"locale_id" INTEGER NOT NULL,
@@ -38,30 +56,318 @@ insert or ignore into locale values
 	(null, "es-ES")
 	;
 
-insert or ignore into `resource` values 
+insert or replace into `resource` values 
 	(null, "person.firstName", "random_row", "person_prop:type=firstName"),
 	(null, "person.lastName", "random_row", "person_prop:type=lastName"),
 	(null, "person.nickName", "random_row", "person_prop:type=nickName"),
-	(null, "person.age", "union", "person.age.baby|person.age.child|person.age.teen|person.age.adult|person.age.mid|person.age.old"),
+	(null, "person.firstName.synth", "markov", "person_prop:type=firstName;order=3;min=3;max=12;locale=fr-FR"),
+	(null, "person.lastName.synth", "markov", "person_prop:type=lastName;order=3;min=3;max=14;locale=fr-FR"),
+	(null, "person.age", "union", "person.age.baby:3|person.age.child:12|person.age.teen:6|person.age.adult:20|person.age.mid:35|person.age.old:24"),
 	(null, "person.age.baby", "int_range", "1..3"),
 	(null, "person.age.child", "int_range", "3..12"),
 	(null, "person.age.teen", "int_range", "12..16"),
 	(null, "person.age.adult", "int_range", "16..30"),
 	(null, "person.age.mid", "int_range", "30..55"),
 	(null, "person.age.old", "int_range", "55..100"),
+	(null, "person.birthDate", "datetime", "-100y..today;layout=date"),
 	(null, "person.phone", "union", "person.phone.mobile|person.phone.land"),
-	(null, "person.phone.mobile", "pattern", "+33 6|7 00..99 00..99 00..99 00..99"),
-	(null, "person.phone.land", "pattern", "+33 1..9!6|7 00..99 00..99 00..99 00..99"),
-	(null, "person.email", "email", "{firstName}.{lastName}@{provider},{lastName}.{firstName}@{provider},{nickName}@provider"),
+	(null, "person.phone.mobile", "phone", "fr-FR;kind=mobile"),
+	(null, "person.phone.land", "phone", "fr-FR;kind=landline"),
+	(null, "person.phone.tollfree", "phone", "fr-FR;kind=tollfree;format=national"),
+	(null, "person.phone.uk", "phone", "en-UK"),
+	(null, "person.phone.us", "phone", "en-US;format=national"),
+	(null, "person.phone.es", "phone", "es-ES"),
+	(null, "person.phone.e164", "phone", "fr-FR;format=e164"),
+	(null, "person.email", "email", "{person.firstName}.{person.lastName}@{provider},{person.lastName}.{person.firstName}@{provider},{person.nickName}@{provider};locale=fr-FR"),
+	(null, "person.fullName", "template", "{person.firstName|title} {person.lastName|title}"),
 	(null, "location.country", "random_row", "location_prop:type=country"),
 	(null, "location.town", "random_row", "location_prop:type=town"),
 	(null, "location.continent", "random_row", "location_prop:type=continent"),
-	(null, "misc.health-insurance", "random_row", "misc_prop:type=health-insurance")
+	(null, "location.address", "address", "fr-FR"),
+	(null, "location.address.us", "address", "en-US"),
+	(null, "location.address.json", "address", "fr-FR;format=json"),
+	(null, "location.geo.france", "geo", "bbox(42.3,-4.8,51.1,8.2);precision=5"),
+	(null, "location.geo.paris", "geo", "radius(48.8566,2.3522,8);precision=5"),
+	(null, "location.geo.paris.wkt", "geo", '{"type":"Polygon","coordinates":[[[2.2241,48.8156],[2.4699,48.8156],[2.4699,48.9022],[2.2241,48.9022],[2.2241,48.8156]]]};format=wkt'),
+	(null, "net.ipv4", "ipv4", "10.0.0.0/8"),
+	(null, "net.ipv4.any", "ipv4", "0.0.0.0/0"),
+	(null, "net.ipv6", "ipv6", "2001:db8::/32"),
+	(null, "net.mac", "mac", null),
+	(null, "net.mac.vmware", "mac", "00:50:56"),
+	(null, "net.hostname", "hostname", null),
+	(null, "net.url", "url", "https|http"),
+	(null, "person.nickName.optional", "nullable", "person.nickName;ratio=0.3"),
+	(null, "person.phone.optional", "nullable", "person.phone;ratio=0.2"),
+	(null, "misc.bool", "bool", null),
+	(null, "misc.bool.active", "bool", "1|0;ratio=0.8"),
+	(null, "person.lastName.title", "filter", "person.lastName|title"),
+	(null, "person.username", "template", "{person.firstName|slug|truncate(1)}{person.lastName|slug|truncate(10)}"),
+	(null, "auth.password", "secret", "password;min=12;max=20;classes=lower|upper|digit|symbol;no_ambiguous;min_entropy=72"),
+	(null, "auth.token", "secret", "token;encoding=base64url;bytes=24;prefix=sk_test_"),
+	(null, "auth.token.hex", "secret", "token;encoding=hex;bytes=16"),
+	(null, "billing.amount.fr", "money", "EUR;min=1;max=5000;locale=fr-FR"),
+	(null, "billing.amount.us", "money", "USD;min=1;max=5000;locale=en-US"),
+	(null, "billing.amount.uk", "money", "GBP;min=1;max=5000;locale=en-UK"),
+	(null, "billing.amount.es", "money", "EUR;min=1;max=5000;locale=es-ES"),
+	(null, "billing.amount.raw", "money", "EUR;min=1;max=5000;format=raw"),
+	(null, "person.avatar", "image", "identicon;source=person.email;size=64"),
+	(null, "person.avatar.file", "image", "identicon;source=person.email;size=128;output=file"),
+	(null, "misc.placeholder", "image", "gradient;size=320x180;format=jpeg;output=file"),
+	(null, "id.sequence", "sequence", "%d"),
+	(null, "id.uuid", "uuid", "v4"),
+	(null, "id.uuid7", "uuid", "v7"),
+	(null, "id.ulid", "ulid", null),
+	(null, "id.snowflake", "snowflake", ";node=1"),
+	(null, "vehicle.plate", "regex", "[A-HJ-NP-TV-Z]{2}-\d{3}-[A-HJ-NP-TV-Z]{2}"),
+	(null, "text.fr.words", "text", "words;min=1;max=5;locale=fr-FR"),
+	(null, "text.fr.sentence", "text", "sentence;locale=fr-FR"),
+	(null, "text.fr.paragraph", "text", "paragraph;locale=fr-FR"),
+	(null, "text.es.words", "text", "words;min=1;max=5;locale=es-ES"),
+	(null, "text.es.sentence", "text", "sentence;locale=es-ES"),
+	(null, "text.es.paragraph", "text", "paragraph;locale=es-ES"),
+	(null, "misc.invoice", "sequence", "INV-%06d;start=1000;step=5"),
+	(null, "person.nir", "nir", ";format=print"),
+	(null, "bank.iban", "iban", "FR;format=print"),
+	(null, "bank.card", "credit_card", "visa|mastercard|amex"),
+	(null, "company.siren", "siren", null),
+	(null, "company.siret", "siret", null),
+	(null, "misc.health-insurance", "random_row", "misc_prop:type=health-insurance"),
+	(null, "misc.emailProvider", "random_row", "misc_prop:type=email-provider;locale=fr-FR")
     ;
+
+insert or replace into `entity` values
+	(null, "person", "{id: id.sequence, firstName: person.firstName, lastName: person.lastName, fullName: '{firstName|title} {lastName|title}', email: '{firstName|slug}.{lastName|slug}@{misc.emailProvider}', age: person.age, birthDate: birthdate(age), phone: person.phone, avatar: identicon(email)}"),
+	(null, "company", "{siret: company.siret, iban: bank.iban, invoice: misc.invoice, amount: billing.amount.fr}"),
+	(null, "host", "{hostname: net.hostname, ipv4: net.ipv4, ipv6: net.ipv6, mac: net.mac}")
+	;
 	
 
 insert or replace into person_prop values 
   (null, 1, "nickName", "le puant"),
   (null, 1, "nickName", "le beau"),
   (null, 1, "nickName", "la peche")
-  ;
\ No newline at end of file
+  ;
+
+insert or ignore into misc_prop values 
+  (null, 1, "email-provider", "gmail.com"),
+  (null, 1, "email-provider", "orange.fr"),
+  (null, 1, "email-provider", "free.fr"),
+  (null, 1, "email-provider", "laposte.net"),
+  (null, 1, "email-provider", "sfr.fr"),
+  (null, 1, "email-provider", "hotmail.fr"),
+  (null, 2, "email-provider", "gmail.com"),
+  (null, 2, "email-provider", "btinternet.com"),
+  (null, 2, "email-provider", "yahoo.co.uk"),
+  (null, 2, "email-provider", "outlook.com"),
+  (null, 3, "email-provider", "gmail.com"),
+  (null, 3, "email-provider", "yahoo.com"),
+  (null, 3, "email-provider", "outlook.com"),
+  (null, 3, "email-provider", "aol.com"),
+  (null, 4, "email-provider", "gmail.com"),
+  (null, 4, "email-provider", "hotmail.es"),
+  (null, 4, "email-provider", "yahoo.es"),
+  (null, 4, "email-provider", "telefonica.net")
+  ;
+
+insert or ignore into location_prop (locale_id, type, value, code) values 
+  (1, "country", "France", "FR"),
+  (1, "country", "Allemagne", "DE"),
+  (1, "country", "Espagne", "ES"),
+  (1, "country", "Italie", "IT"),
+  (1, "country", "Belgique", "BE"),
+  (1, "country", "Suisse", "CH"),
+  (1, "country", "Royaume-Uni", "GB"),
+  (1, "country", "États-Unis", "US"),
+  (1, "country", "Canada", "CA"),
+  (1, "country", "Japon", "JP"),
+  (1, "continent", "Europe", null),
+  (1, "continent", "Afrique", null),
+  (1, "continent", "Asie", null),
+  (1, "continent", "Amérique du Nord", null),
+  (1, "continent", "Amérique du Sud", null),
+  (1, "continent", "Océanie", null),
+  (1, "continent", "Antarctique", null),
+  (1, "town", "Paris", "75001"),
+  (1, "town", "Marseille", "13001"),
+  (1, "town", "Lyon", "69001"),
+  (1, "town", "Toulouse", "31000"),
+  (1, "town", "Nice", "06000"),
+  (1, "town", "Nantes", "44000"),
+  (1, "town", "Strasbourg", "67000"),
+  (1, "town", "Montpellier", "34000"),
+  (1, "town", "Bordeaux", "33000"),
+  (1, "town", "Lille", "59000"),
+  (1, "town", "Rennes", "35000"),
+  (1, "town", "Reims", "51100"),
+  (1, "town", "Le Havre", "76600"),
+  (1, "town", "Saint-Étienne", "42000"),
+  (1, "town", "Toulon", "83000"),
+  (1, "town", "Grenoble", "38000"),
+  (1, "town", "Dijon", "21000"),
+  (1, "town", "Angers", "49000"),
+  (1, "town", "Nîmes", "30000"),
+  (1, "town", "Clermont-Ferrand", "63000"),
+  (1, "street-type", "rue", null),
+  (1, "street-type", "avenue", null),
+  (1, "street-type", "boulevard", null),
+  (1, "street-type", "place", null),
+  (1, "street-type", "allée", null),
+  (1, "street-type", "impasse", null),
+  (1, "street-type", "chemin", null),
+  (1, "street-type", "quai", null),
+  (1, "street-name", "de la République", null),
+  (1, "street-name", "Victor Hugo", null),
+  (1, "street-name", "Jean Jaurès", null),
+  (1, "street-name", "de la Paix", null),
+  (1, "street-name", "Pasteur", null),
+  (1, "street-name", "du Général de Gaulle", null),
+  (1, "street-name", "des Lilas", null),
+  (1, "street-name", "de la Gare", null),
+  (1, "street-name", "Voltaire", null),
+  (1, "street-name", "Émile Zola", null),
+  (2, "country", "France", "FR"),
+  (2, "country", "Germany", "DE"),
+  (2, "country", "Spain", "ES"),
+  (2, "country", "Italy", "IT"),
+  (2, "country", "Belgium", "BE"),
+  (2, "country", "Switzerland", "CH"),
+  (2, "country", "United Kingdom", "GB"),
+  (2, "country", "United States", "US"),
+  (2, "country", "Canada", "CA"),
+  (2, "country", "Japan", "JP"),
+  (2, "continent", "Europe", null),
+  (2, "continent", "Africa", null),
+  (2, "continent", "Asia", null),
+  (2, "continent", "North America", null),
+  (2, "continent", "South America", null),
+  (2, "continent", "Oceania", null),
+  (2, "continent", "Antarctica", null),
+  (2, "town", "London", "SW1A 1AA"),
+  (2, "town", "Manchester", "M1 1AE"),
+  (2, "town", "Birmingham", "B1 1BB"),
+  (2, "town", "Leeds", "LS1 1UR"),
+  (2, "town", "Glasgow", "G1 1XQ"),
+  (2, "town", "Liverpool", "L1 8JQ"),
+  (2, "town", "Bristol", "BS1 4DJ"),
+  (2, "town", "Edinburgh", "EH1 1YZ"),
+  (2, "town", "Sheffield", "S1 2HE"),
+  (2, "town", "Cardiff", "CF10 1EP"),
+  (2, "street-type", "Street", null),
+  (2, "street-type", "Road", null),
+  (2, "street-type", "Lane", null),
+  (2, "street-type", "Avenue", null),
+  (2, "street-type", "Close", null),
+  (2, "street-type", "Crescent", null),
+  (2, "street-type", "Drive", null),
+  (2, "street-name", "High", null),
+  (2, "street-name", "Church", null),
+  (2, "street-name", "Victoria", null),
+  (2, "street-name", "Station", null),
+  (2, "street-name", "Park", null),
+  (2, "street-name", "Mill", null),
+  (2, "street-name", "King", null),
+  (2, "street-name", "Queen", null),
+  (2, "street-name", "York", null),
+  (2, "street-name", "Green", null),
+  (3, "country", "France", "FR"),
+  (3, "country", "Germany", "DE"),
+  (3, "country", "Spain", "ES"),
+  (3, "country", "Italy", "IT"),
+  (3, "country", "Belgium", "BE"),
+  (3, "country", "Switzerland", "CH"),
+  (3, "country", "United Kingdom", "GB"),
+  (3, "country", "United States", "US"),
+  (3, "country", "Canada", "CA"),
+  (3, "country", "Japan", "JP"),
+  (3, "continent", "Europe", null),
+  (3, "continent", "Africa", null),
+  (3, "continent", "Asia", null),
+  (3, "continent", "North America", null),
+  (3, "continent", "South America", null),
+  (3, "continent", "Oceania", null),
+  (3, "continent", "Antarctica", null),
+  (3, "town", "New York", "10001"),
+  (3, "town", "Los Angeles", "90001"),
+  (3, "town", "Chicago", "60601"),
+  (3, "town", "Houston", "77001"),
+  (3, "town", "Phoenix", "85001"),
+  (3, "town", "Philadelphia", "19102"),
+  (3, "town", "San Antonio", "78205"),
+  (3, "town", "San Diego", "92101"),
+  (3, "town", "Dallas", "75201"),
+  (3, "town", "Austin", "78701"),
+  (3, "town", "Seattle", "98101"),
+  (3, "town", "Boston", "02108"),
+  (3, "street-type", "Street", null),
+  (3, "street-type", "Avenue", null),
+  (3, "street-type", "Boulevard", null),
+  (3, "street-type", "Road", null),
+  (3, "street-type", "Drive", null),
+  (3, "street-type", "Lane", null),
+  (3, "street-type", "Court", null),
+  (3, "street-name", "Main", null),
+  (3, "street-name", "Oak", null),
+  (3, "street-name", "Pine", null),
+  (3, "street-name", "Maple", null),
+  (3, "street-name", "Cedar", null),
+  (3, "street-name", "Elm", null),
+  (3, "street-name", "Washington", null),
+  (3, "street-name", "Lake", null),
+  (3, "street-name", "Hill", null),
+  (3, "street-name", "Park", null),
+  (3, "town-state", "New York", "NY"),
+  (3, "town-state", "Los Angeles", "CA"),
+  (3, "town-state", "Chicago", "IL"),
+  (3, "town-state", "Houston", "TX"),
+  (3, "town-state", "Phoenix", "AZ"),
+  (3, "town-state", "Philadelphia", "PA"),
+  (3, "town-state", "San Antonio", "TX"),
+  (3, "town-state", "San Diego", "CA"),
+  (3, "town-state", "Dallas", "TX"),
+  (3, "town-state", "Austin", "TX"),
+  (3, "town-state", "Seattle", "WA"),
+  (3, "town-state", "Boston", "MA"),
+  (4, "country", "Francia", "FR"),
+  (4, "country", "Alemania", "DE"),
+  (4, "country", "España", "ES"),
+  (4, "country", "Italia", "IT"),
+  (4, "country", "Bélgica", "BE"),
+  (4, "country", "Suiza", "CH"),
+  (4, "country", "Reino Unido", "GB"),
+  (4, "country", "Estados Unidos", "US"),
+  (4, "country", "Canadá", "CA"),
+  (4, "country", "Japón", "JP"),
+  (4, "continent", "Europa", null),
+  (4, "continent", "África", null),
+  (4, "continent", "Asia", null),
+  (4, "continent", "América del Norte", null),
+  (4, "continent", "América del Sur", null),
+  (4, "continent", "Oceanía", null),
+  (4, "continent", "Antártida", null),
+  (4, "town", "Madrid", "28013"),
+  (4, "town", "Barcelona", "08001"),
+  (4, "town", "Valencia", "46001"),
+  (4, "town", "Sevilla", "41001"),
+  (4, "town", "Zaragoza", "50001"),
+  (4, "town", "Málaga", "29001"),
+  (4, "town", "Murcia", "30001"),
+  (4, "town", "Palma", "07001"),
+  (4, "town", "Bilbao", "48001"),
+  (4, "town", "Alicante", "03001"),
+  (4, "street-type", "Calle", null),
+  (4, "street-type", "Avenida", null),
+  (4, "street-type", "Plaza", null),
+  (4, "street-type", "Paseo", null),
+  (4, "street-type", "Camino", null),
+  (4, "street-type", "Ronda", null),
+  (4, "street-name", "Mayor", null),
+  (4, "street-name", "de Alcalá", null),
+  (4, "street-name", "Gran Vía", null),
+  (4, "street-name", "del Sol", null),
+  (4, "street-name", "de la Constitución", null),
+  (4, "street-name", "de Cervantes", null),
+  (4, "street-name", "San Juan", null),
+  (4, "street-name", "del Carmen", null),
+  (4, "street-name", "Real", null),
+  (4, "street-name", "de la Paz", null)
+  ;
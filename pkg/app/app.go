@@ -4,11 +4,13 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -25,6 +27,10 @@ import (
 	"github.com/welschmorgan/datagen/pkg/seed"
 )
 
+// GENERATE_BUFFER_SIZE is the number of values a run generates ahead of the
+// output.
+const GENERATE_BUFFER_SIZE = 64
+
 func DBPath() string {
 	return fmt.Sprintf("%s/%s", cache.RootCacheDir(), "resources.db")
 }
@@ -35,7 +41,13 @@ type App struct {
 	options   *Options
 	config    *config.Config
 	resources []*models.Resource
+	entities  []*models.Entity
 	locales   []*models.Locale
+	filtered  sync.Map
+	// schemas holds the fields of entities, parsed on load
+	schemas map[*models.Entity]*generators.EntitySchema
+	// out is where generated values are printed
+	out io.Writer
 }
 
 func New(opts *Options) *App {
@@ -44,6 +56,7 @@ func New(opts *Options) *App {
 		reg:     nil,
 		options: opts,
 		config:  config.Default(),
+		out:     os.Stdout,
 	}
 }
 
@@ -89,19 +102,15 @@ func (a *App) Init() error {
 		}
 	}
 
-	a.reg = generators.NewRegistry()
-	a.reg.AddType(generators.INT_RANGE_GENERATOR_NAME, generators.AllocateGeneratorIntRange)
-	a.reg.AddType(generators.RANDOM_DB_ROW_GENERATOR_NAME, generators.AllocateGeneratorRandomDB(a.db))
-	a.reg.AddType(generators.PATTERN_GENERATOR_NAME, generators.AllocateGeneratorPattern)
-	a.reg.AddType(generators.UNION_GENERATOR_NAME, generators.AllocateGeneratorUnion(a.db, func(name string) generator.Generator {
-		res, err := a.GetResource(name)
-		if err != nil {
-			log.Printf("Failed to get variant '%s' generator, %s", name, err)
-			return nil
-		}
+	a.load()
 
-		return res.Generator
-	}))
+	return nil
+}
+
+// load allocates the generators of the DB resources then reads the entities,
+// dropping the invalid ones.
+func (a *App) load() {
+	a.reg = a.newRegistry(a.resourceGenerator)
 
 	resources := models.LoadResources(a.db)
 	for _, r := range resources {
@@ -119,8 +128,111 @@ func (a *App) Init() error {
 			}
 		}
 	}
+	a.validateResources()
+	a.loadEntities()
+}
 
-	return nil
+// newRegistry registers every generator type, composite generators resolving
+// the resources they reference through resGetter.
+func (a *App) newRegistry(resGetter func(name string) generator.Generator) *generators.Registry {
+	reg := generators.NewRegistry()
+	reg.AddType(generators.INT_RANGE_GENERATOR_NAME, generators.AllocateGeneratorIntRange)
+	reg.AddType(generators.SEQUENCE_GENERATOR_NAME, generators.AllocateGeneratorSequence)
+	reg.AddType(generators.FLOAT_RANGE_GENERATOR_NAME, generators.AllocateGeneratorFloatRange)
+	reg.AddType(generators.DATETIME_GENERATOR_NAME, generators.AllocateGeneratorDatetime)
+	reg.AddType(generators.UUID_GENERATOR_NAME, generators.AllocateGeneratorUUID)
+	reg.AddType(generators.ULID_GENERATOR_NAME, generators.AllocateGeneratorULID)
+	reg.AddType(generators.SNOWFLAKE_GENERATOR_NAME, generators.AllocateGeneratorSnowflake)
+	reg.AddType(generators.REGEX_GENERATOR_NAME, generators.AllocateGeneratorRegex)
+	reg.AddType(generators.IBAN_GENERATOR_NAME, generators.AllocateGeneratorIBAN)
+	reg.AddType(generators.CREDIT_CARD_GENERATOR_NAME, generators.AllocateGeneratorCreditCard)
+	reg.AddType(generators.NIR_GENERATOR_NAME, generators.AllocateGeneratorNationalId(generators.NIR_GENERATOR_NAME))
+	reg.AddType(generators.SIREN_GENERATOR_NAME, generators.AllocateGeneratorNationalId(generators.SIREN_GENERATOR_NAME))
+	reg.AddType(generators.SIRET_GENERATOR_NAME, generators.AllocateGeneratorNationalId(generators.SIRET_GENERATOR_NAME))
+	reg.AddType(generators.RANDOM_DB_ROW_GENERATOR_NAME, generators.AllocateGeneratorRandomDB(a.db))
+	reg.AddType(generators.MARKOV_GENERATOR_NAME, generators.AllocateGeneratorMarkov(a.db))
+	reg.AddType(generators.TEXT_GENERATOR_NAME, generators.AllocateGeneratorText(a.db))
+	reg.AddType(generators.ADDRESS_GENERATOR_NAME, generators.AllocateGeneratorAddress(a.db))
+	reg.AddType(generators.GEO_GENERATOR_NAME, generators.AllocateGeneratorGeo)
+	reg.AddType(generators.IPV4_GENERATOR_NAME, generators.AllocateGeneratorIPv4)
+	reg.AddType(generators.IPV6_GENERATOR_NAME, generators.AllocateGeneratorIPv6)
+	reg.AddType(generators.MAC_GENERATOR_NAME, generators.AllocateGeneratorMAC)
+	reg.AddType(generators.HOSTNAME_GENERATOR_NAME, generators.AllocateGeneratorHostname)
+	reg.AddType(generators.URL_GENERATOR_NAME, generators.AllocateGeneratorURL)
+	reg.AddType(generators.NULLABLE_GENERATOR_NAME, generators.AllocateGeneratorNullable(resGetter))
+	reg.AddType(generators.BOOL_GENERATOR_NAME, generators.AllocateGeneratorBool)
+	reg.AddType(generators.FILTER_GENERATOR_NAME, generators.AllocateGeneratorFilter(resGetter))
+	reg.AddType(generators.SECRET_GENERATOR_NAME, generators.AllocateGeneratorSecret)
+	reg.AddType(generators.PHONE_GENERATOR_NAME, generators.AllocateGeneratorPhone)
+	reg.AddType(generators.MONEY_GENERATOR_NAME, generators.AllocateGeneratorMoney)
+	reg.AddType(generators.IMAGE_GENERATOR_NAME, generators.AllocateGeneratorImage(resGetter))
+	reg.AddType(generators.PATTERN_GENERATOR_NAME, generators.AllocateGeneratorPattern)
+	reg.AddType(generators.PATTERN_V2_GENERATOR_NAME, generators.AllocateGeneratorPatternV2)
+	reg.AddType(generators.UNION_GENERATOR_NAME, generators.AllocateGeneratorUnion(a.db, resGetter))
+	reg.AddType(generators.EMAIL_GENERATOR_NAME, generators.AllocateGeneratorEmail(a.db, resGetter))
+	reg.AddType(generators.TEMPLATE_GENERATOR_NAME, generators.AllocateGeneratorTemplate(resGetter))
+	return reg
+}
+
+// validateResources drops resources whose generator references unknown
+// resources, until every remaining one is valid.
+func (a *App) validateResources() {
+	for {
+		valid := []*models.Resource{}
+		for _, r := range a.resources {
+			if v, ok := r.Generator.(generators.Validator); ok {
+				if err := v.Validate(); err != nil {
+					slog.Error(fmt.Sprintf("Invalid resource #%d '%s'", r.Id, r.Name), "err", err, "generator", *r.GeneratorName)
+					continue
+				}
+			}
+			valid = append(valid, r)
+		}
+		if len(valid) == len(a.resources) {
+			return
+		}
+		a.resources = valid
+	}
+}
+
+// loadEntities reads the DB entities, those of the user configuration
+// replacing the DB ones of the same name, then drops the invalid ones.
+func (a *App) loadEntities() {
+	entities, err := models.LoadEntities(a.db)
+	if err != nil {
+		slog.Warn("Failed to load DB entities, run with --seed to update the DB", "err", err)
+		entities = []*models.Entity{}
+	}
+	for _, e := range a.config.Entities {
+		if i := slices.IndexFunc(entities, func(x *models.Entity) bool { return strings.EqualFold(x.Name, e.Name) }); i != -1 {
+			entities[i] = models.NewEntity(entities[i].Id, e.Name, e.Fields)
+		} else {
+			entities = append(entities, models.NewEntity(0, e.Name, e.Fields))
+		}
+	}
+	a.schemas = map[*models.Entity]*generators.EntitySchema{}
+	for _, e := range entities {
+		schema, err := generators.ParseEntity(e.Fields)
+		if err == nil {
+			err = generators.NewRecordGenerator(&a.options.generator, e.Name, schema, a.resourceGenerator).Validate()
+		}
+		if err != nil {
+			slog.Error(fmt.Sprintf("Invalid entity '%s'", e.Name), "err", err, "fields", e.Fields)
+			continue
+		}
+		a.entities = append(a.entities, e)
+		a.schemas[e] = schema
+		slog.Debug(fmt.Sprintf("Found entity '%s'", e.Name), "fields", e.Fields)
+	}
+}
+
+func (a *App) GetEntity(name string) (*models.Entity, error) {
+	for _, e := range a.entities {
+		if strings.EqualFold(e.Name, name) {
+			return e, nil
+		}
+	}
+	return nil, fmt.Errorf("failed to find entity '%s'", name)
 }
 
 func (a *App) GetResource(name string) (*models.Resource, error) {
@@ -132,51 +244,218 @@ func (a *App) GetResource(name string) (*models.Resource, error) {
 	return nil, fmt.Errorf("failed to find resource '%s'", name)
 }
 
+func (a *App) resourceGenerator(name string) generator.Generator {
+	if strings.Contains(name, "|") {
+		return a.filteredGenerator(name)
+	}
+	res, err := a.GetResource(name)
+	if err != nil {
+		log.Printf("Failed to get variant '%s' generator, %s", name, err)
+		return nil
+	}
+
+	return res.Generator
+}
+
+// filteredGenerator builds, once per chain, the generator of a reference such
+// as 'person.lastName|title|ascii'.
+func (a *App) filteredGenerator(chain string) generator.Generator {
+	if g, ok := a.filtered.Load(chain); ok {
+		return g.(generator.Generator)
+	}
+	g := newFilteredGenerator(&a.options.generator, chain, a.resourceGenerator)
+	if g == nil {
+		return nil
+	}
+	stored, _ := a.filtered.LoadOrStore(chain, g)
+	return stored.(generator.Generator)
+}
+
+// newFilteredGenerator builds the generator of a filtered reference, the
+// filtered resource being resolved through resGetter.
+func newFilteredGenerator(options *generator.GeneratorOptions, chain string, resGetter func(name string) generator.Generator) generator.Generator {
+	resource, filters, err := generators.ParseFilterChain(chain)
+	if err != nil {
+		log.Printf("Failed to parse filtered reference '%s', %s", chain, err)
+		return nil
+	}
+	if resGetter(resource) == nil {
+		return nil
+	}
+	return generators.NewFilterGenerator(options, resource, filters, resGetter)
+}
+
+// generatorScope allocates, on first use, its own generators for the
+// resources of a run, so that concurrent runs share neither generators nor
+// random streams.
+type generatorScope struct {
+	app        *App
+	options    *generator.GeneratorOptions
+	reg        *generators.Registry
+	generators map[string]generator.Generator
+}
+
+func (a *App) newScope(stream string) *generatorScope {
+	s := &generatorScope{
+		app:        a,
+		options:    a.options.generator.Fork(stream),
+		generators: map[string]generator.Generator{},
+	}
+	s.reg = a.newRegistry(s.resourceGenerator)
+	return s
+}
+
+func (s *generatorScope) resourceGenerator(name string) generator.Generator {
+	if g, ok := s.generators[name]; ok {
+		return g
+	}
+	var g generator.Generator
+	if strings.Contains(name, "|") {
+		if g = newFilteredGenerator(s.options, name, s.resourceGenerator); g == nil {
+			return nil
+		}
+	} else {
+		res, err := s.app.GetResource(name)
+		if err != nil {
+			log.Printf("Failed to get variant '%s' generator, %s", name, err)
+			return nil
+		}
+		if g, err = generators.GeneratorForResource(s.options, res, s.reg); err != nil {
+			log.Printf("Failed to allocate '%s' generator, %s", name, err)
+			return nil
+		}
+	}
+	s.generators[name] = g
+	return g
+}
+
 func (a *App) Generate() error {
+	if len(a.options.entities) > 0 {
+		if len(a.options.resources) > 0 {
+			return fmt.Errorf("--entity and --resource cannot be combined")
+		}
+		return a.GenerateRecords()
+	}
+
 	type Result struct {
+		value string
+		err   error
+	}
+
+	type Run struct {
 		resource  *models.Resource
 		generator generator.Generator
-		round     int
-		value     string
+		values    chan Result
 	}
 
-	value_chan := make(chan Result)
-	var wgGen sync.WaitGroup
-	var wgOut sync.WaitGroup
-
-	nTasks := a.options.count * len(a.options.resources)
-	wgGen.Add(nTasks)
-	wgOut.Add(nTasks)
-
+	runs := []*Run{}
+	occurrences := map[string]int{}
 	for _, user_res := range a.options.resources {
 		app_res, err := a.GetResource(user_res)
 		if err != nil {
 			return err
 		}
-		gen := app_res.Generator
+		// streams are named after resources rather than flag positions, so
+		// that adding a resource doesn't change the values of the others
+		key := strings.ToLower(app_res.Name)
+		scope := a.newScope(fmt.Sprintf("%s#%d", key, occurrences[key]))
+		occurrences[key]++
+		gen := scope.resourceGenerator(app_res.Name)
+		if gen == nil {
+			return fmt.Errorf("failed to allocate resource '%s'", app_res.Name)
+		}
+		runs = append(runs, &Run{resource: app_res, generator: gen, values: make(chan Result, GENERATE_BUFFER_SIZE)})
+	}
 
+	for _, run := range runs {
 		go func() {
-			for i := range a.options.count {
-				if value, err := gen.Next(); err != nil {
-					panic(fmt.Errorf("failed to generate value #%d: %s", i, err))
-				} else {
-					value_chan <- Result{resource: app_res, generator: gen, round: i, value: value}
-					wgGen.Done()
+			defer close(run.values)
+			for range a.options.count {
+				value, err := run.generator.Next()
+				run.values <- Result{value: value, err: err}
+				if err != nil {
+					return
 				}
 			}
 		}()
+	}
+
+	// rounds are printed in order whatever the pace of each run, keeping
+	// seeded outputs identical
+	for i := range a.options.count {
+		for _, run := range runs {
+			res := <-run.values
+			if res.err != nil {
+				return fmt.Errorf("failed to generate value #%d: %s", i, res.err)
+			}
+			fmt.Fprintln(a.out, a.options.output.fmt(run.resource, run.generator, i, res.value))
+		}
+	}
+
+	return nil
+}
+
+// GenerateRecords prints the records of each entity in turn, records being
+// generated concurrently.
+func (a *App) GenerateRecords() error {
+	type Result struct {
+		record *generators.Record
+		err    error
+	}
+
+	type Run struct {
+		entity    *models.Entity
+		generator *generators.RecordGenerator
+		records   chan Result
+	}
+
+	runs := []*Run{}
+	occurrences := map[string]int{}
+	for _, name := range a.options.entities {
+		entity, err := a.GetEntity(name)
+		if err != nil {
+			return err
+		}
+		key := strings.ToLower(entity.Name)
+		scope := a.newScope(fmt.Sprintf("entity:%s#%d", key, occurrences[key]))
+		occurrences[key]++
+		gen := generators.NewRecordGenerator(scope.options, entity.Name, a.schemas[entity], scope.resourceGenerator)
+		runs = append(runs, &Run{entity: entity, generator: gen, records: make(chan Result, GENERATE_BUFFER_SIZE)})
+	}
+
+	for _, run := range runs {
 		go func() {
+			defer close(run.records)
 			for range a.options.count {
-				res := <-value_chan
-				output := a.options.output.fmt(res.resource, res.generator, res.round, res.value)
-				fmt.Println(output)
-				wgOut.Done()
+				record, err := run.generator.Next()
+				run.records <- Result{record: record, err: err}
+				if err != nil {
+					return
+				}
 			}
 		}()
 	}
 
-	wgGen.Wait()
-	wgOut.Wait()
+	for _, run := range runs {
+		header, ok, err := a.options.records.header(run.generator.Fields())
+		if err != nil {
+			return err
+		}
+		if ok {
+			fmt.Fprintln(a.out, header)
+		}
+		for i := range a.options.count {
+			res := <-run.records
+			if res.err != nil {
+				return fmt.Errorf("failed to generate %s record #%d: %s", run.entity.Name, i, res.err)
+			}
+			line, err := a.options.records.fmt(res.record)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(a.out, line)
+		}
+	}
 
 	return nil
 }
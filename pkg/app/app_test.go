@@ -0,0 +1,125 @@
+package app
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/seed"
+)
+
+// testOptions returns the options of a seeded run of count values.
+func testOptions(count int) *Options {
+	opts := &Options{
+		count:      count,
+		generator:  *generator.NewGeneratorOptions(),
+		nullString: DEFAULT_NULL_STRING,
+	}
+	opts.generator.SetRandSeed(42)
+	opts.output = NewDefaultOutputFormatter(opts.nullString)
+	opts.records, _ = NewRecordFormatter(RECORD_FORMAT_JSON, opts.nullString)
+	return opts
+}
+
+// testApp returns an app loaded from a DB seeded with the default schema,
+// printing to its buffer.
+func testApp(t *testing.T, opts *Options) (*App, *bytes.Buffer) {
+	schema, err := os.ReadFile(filepath.Join("..", "..", "assets", "seed.sql"))
+	if err != nil {
+		t.Fatalf("failed to read seed schema, %s", err)
+	}
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "datagen.db"))
+	if err != nil {
+		t.Fatalf("failed to open DB, %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if err := seed.NewQueryUploader(db, string(schema)).Upload(nil); err != nil {
+		t.Fatalf("failed to seed DB, %s", err)
+	}
+	// names are fetched by remote seeds, so provide a few
+	corpus := `insert into person_prop (locale_id, type, value) values
+		(1, 'firstName', 'Jean'), (1, 'firstName', 'Marie'), (1, 'firstName', 'Hélène'), (1, 'firstName', 'Pierre'), (1, 'firstName', 'Camille'),
+		(1, 'lastName', 'Dupont'), (1, 'lastName', 'Martin'), (1, 'lastName', 'Bernard'), (1, 'lastName', 'Durand'), (1, 'lastName', 'Lefebvre')`
+	if _, err := db.Exec(corpus); err != nil {
+		t.Fatalf("failed to insert names, %s", err)
+	}
+	out := &bytes.Buffer{}
+	a := New(opts)
+	a.db = db
+	a.out = out
+	a.load()
+	return a, out
+}
+
+// generate runs a with the given resources or entities, returning the lines
+// it printed.
+func generate(t *testing.T, resources, entities []string) []string {
+	return generateWith(t, testOptions(20), resources, entities)
+}
+
+func generateWith(t *testing.T, opts *Options, resources, entities []string) []string {
+	opts.resources = resources
+	opts.entities = entities
+	a, out := testApp(t, opts)
+	if err := a.Generate(); err != nil {
+		t.Fatalf("failed to generate %v%v, %s", resources, entities, err)
+	}
+	return strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+}
+
+func TestGenerateIsReproducible(t *testing.T) {
+	resources := []string{"person.firstName", "person.fullName", "person.phone", "location.address", "person.age", "person.email", "id.uuid7", "person.nickName"}
+	lines := generate(t, resources, nil)
+	if len(lines) != 20*len(resources) {
+		t.Fatalf("expected %d values but got %d", 20*len(resources), len(lines))
+	}
+	if again := generate(t, resources, nil); !slices.Equal(lines, again) {
+		t.Errorf("expected identical outputs with the same seed")
+	}
+	// runs don't depend on the others, so adding one changes none of them
+	more := generate(t, append([]string{"person.firstName", "net.ipv4"}, resources...), nil)
+	for _, line := range lines {
+		if !slices.Contains(more, line) {
+			t.Errorf("expected '%s' to be generated again when adding resources", line)
+		}
+	}
+}
+
+func TestGenerateRecordsIsReproducible(t *testing.T) {
+	lines := generate(t, nil, []string{"person", "company", "host"})
+	if len(lines) != 60 {
+		t.Fatalf("expected 60 records but got %d", len(lines))
+	}
+	if again := generate(t, nil, []string{"person", "company", "host"}); !slices.Equal(lines, again) {
+		t.Errorf("expected identical records with the same seed")
+	}
+	if alone := generate(t, nil, []string{"company"}); !slices.Equal(lines[20:40], alone) {
+		t.Errorf("expected company records not to depend on the other entities")
+	}
+}
+
+func TestGenerateRecordsAsCSV(t *testing.T) {
+	opts := testOptions(3)
+	opts.records, _ = NewRecordFormatter(RECORD_FORMAT_CSV, opts.nullString)
+	lines := generateWith(t, opts, nil, []string{"host", "company"})
+	if len(lines) != 8 {
+		t.Fatalf("expected a header and 3 rows per entity but got %v", lines)
+	}
+	for i, header := range []string{"hostname,ipv4,ipv6,mac", "siret,iban,invoice,amount"} {
+		if lines[i*4] != header {
+			t.Errorf("expected header '%s' but got '%s'", header, lines[i*4])
+		}
+		for _, row := range lines[i*4+1 : i*4+4] {
+			values, err := csv.NewReader(strings.NewReader(row)).Read()
+			if err != nil || len(values) != 4 {
+				t.Errorf("expected a row of 4 values but got '%s' (%v)", row, err)
+			}
+		}
+	}
+}
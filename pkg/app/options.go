@@ -1,42 +1,135 @@
 package app
 
 import (
+	"encoding/csv"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/welschmorgan/datagen/pkg/config"
 	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
 	"github.com/welschmorgan/datagen/pkg/models"
 )
 
 const DEFAULT_ITEMS_COUNT = 100
+const DEFAULT_NULL_STRING = "NULL"
 
+// OutputFormatter renders generated values, including generator.NULL_VALUE
+// which must never be printed as is.
 type OutputFormatter interface {
 	fmt(r *models.Resource, g generator.Generator, round int, value string) string
 }
 
 type DefaultOutputFormatter struct {
 	OutputFormatter
+
+	nullString string
 }
 
-func NewDefaultOutputFormatter() *DefaultOutputFormatter {
-	return &DefaultOutputFormatter{}
+func NewDefaultOutputFormatter(nullString string) *DefaultOutputFormatter {
+	return &DefaultOutputFormatter{
+		nullString: nullString,
+	}
 }
 
 func (f *DefaultOutputFormatter) fmt(r *models.Resource, g generator.Generator, round int, value string) string {
+	if generator.IsNull(value) {
+		value = f.nullString
+	}
 	return fmt.Sprintf("[%s:%s #%d] %s", r.Name, g.GetName(), round, value)
 }
 
+const (
+	RECORD_FORMAT_JSON = "json"
+	RECORD_FORMAT_CSV  = "csv"
+)
+
+// RecordFormatter renders the records of an entity, header returning the
+// line to print before the first record, if any.
+type RecordFormatter interface {
+	header(fields []string) (string, bool, error)
+	fmt(r *generators.Record) (string, error)
+}
+
+// NewRecordFormatter returns the formatter of format, either 'json' or 'csv'.
+func NewRecordFormatter(format string, nullString string) (RecordFormatter, error) {
+	switch format {
+	case RECORD_FORMAT_JSON:
+		return &JSONRecordFormatter{}, nil
+	case RECORD_FORMAT_CSV:
+		return &CSVRecordFormatter{nullString: nullString}, nil
+	}
+	return nil, fmt.Errorf("invalid record format '%s', expected 'json' or 'csv'", format)
+}
+
+// JSONRecordFormatter prints a JSON object per record.
+type JSONRecordFormatter struct {
+	RecordFormatter
+}
+
+func (f *JSONRecordFormatter) header(fields []string) (string, bool, error) {
+	return "", false, nil
+}
+
+func (f *JSONRecordFormatter) fmt(r *generators.Record) (string, error) {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to format record, %s", err)
+	}
+	return string(data), nil
+}
+
+// CSVRecordFormatter prints the field names, then a row per record.
+type CSVRecordFormatter struct {
+	RecordFormatter
+
+	nullString string
+}
+
+func (f *CSVRecordFormatter) row(values []string) (string, error) {
+	buf := strings.Builder{}
+	w := csv.NewWriter(&buf)
+	if err := w.Write(values); err != nil {
+		return "", fmt.Errorf("failed to format record, %s", err)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return "", fmt.Errorf("failed to format record, %s", err)
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func (f *CSVRecordFormatter) header(fields []string) (string, bool, error) {
+	line, err := f.row(fields)
+	return line, true, err
+}
+
+func (f *CSVRecordFormatter) fmt(r *generators.Record) (string, error) {
+	values := []string{}
+	for _, v := range r.Values {
+		if generator.IsNull(v) {
+			v = f.nullString
+		}
+		values = append(values, v)
+	}
+	return f.row(values)
+}
+
 type Options struct {
 	verbose     bool
 	resources   ResourceList
+	entities    ResourceList
 	count       int
 	output      OutputFormatter
+	records     RecordFormatter
 	generator   generator.GeneratorOptions
 	seed        bool
 	resetConfig bool
 	configPath  string
+	nullString  string
 }
 
 type ResourceList []string
@@ -57,20 +150,43 @@ func ParseOptions() *Options {
 	opt := Options{
 		verbose:     false,
 		resources:   []string{},
-		output:      NewDefaultOutputFormatter(),
+		entities:    []string{},
+		output:      nil,
+		records:     nil,
 		count:       0,
 		generator:   *generator.NewGeneratorOptions(),
 		seed:        false,
 		resetConfig: false,
 		configPath:  config.DefaultPath(),
+		nullString:  DEFAULT_NULL_STRING,
 	}
 	flag.BoolVar(&opt.verbose, "verbose", opt.verbose, "show additional log messages")
 	flag.Var(&opt.resources, "resource", "generate a dataset with the specified type")
+	flag.Var(&opt.entities, "entity", "generate records of the specified entity")
 	flag.IntVar(&opt.count, "count", DEFAULT_ITEMS_COUNT, "generate this number of items")
 	flag.BoolVar(&opt.generator.OnlyUniqueValues, "unique", opt.generator.OnlyUniqueValues, "only generate unique values")
 	flag.BoolVar(&opt.seed, "seed", opt.seed, "seed DB from various places")
 	flag.BoolVar(&opt.resetConfig, "reset-config", opt.resetConfig, "reset configuration to default values")
 	flag.StringVar(&opt.configPath, "config-path", opt.configPath, "define the user configuration path to be loaded")
+	flag.StringVar(&opt.nullString, "null-string", opt.nullString, "render null values with this string")
+	flag.Func("rand-seed", "seed random generators, runs with the same seed, DB and config yielding the same values", func(v string) error {
+		seed, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid seed '%s', expected an unsigned integer", v)
+		}
+		opt.generator.SetRandSeed(seed)
+		return nil
+	})
+	recordFormat := RECORD_FORMAT_JSON
+	flag.Func("record-format", "print entity records as 'json' objects or 'csv' rows", func(v string) error {
+		if _, err := NewRecordFormatter(v, ""); err != nil {
+			return err
+		}
+		recordFormat = v
+		return nil
+	})
 	flag.Parse()
+	opt.output = NewDefaultOutputFormatter(opt.nullString)
+	opt.records, _ = NewRecordFormatter(recordFormat, opt.nullString)
 	return &opt
 }
@@ -0,0 +1,73 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+	"github.com/welschmorgan/datagen/pkg/models"
+)
+
+func TestDefaultOutputFormatterNulls(t *testing.T) {
+	res := models.NewResource(1, "person.nickName", nil, nil)
+	gen := generators.NewCacheGenerator(generator.NewGeneratorOptions(), "nullable", nil)
+	for _, c := range []struct {
+		nullString string
+		value      string
+		expected   string
+	}{
+		{DEFAULT_NULL_STRING, generator.NULL_VALUE, "[person.nickName:nullable #3] NULL"},
+		{DEFAULT_NULL_STRING, "", "[person.nickName:nullable #3] "},
+		{DEFAULT_NULL_STRING, "null", "[person.nickName:nullable #3] null"},
+		{"", generator.NULL_VALUE, "[person.nickName:nullable #3] "},
+		{"\\N", generator.NULL_VALUE, "[person.nickName:nullable #3] \\N"},
+	} {
+		if got := NewDefaultOutputFormatter(c.nullString).fmt(res, gen, 3, c.value); got != c.expected {
+			t.Errorf("expected %q for %q with null string %q but got %q", c.expected, c.value, c.nullString, got)
+		}
+	}
+}
+
+func TestRecordFormatterNulls(t *testing.T) {
+	record := &generators.Record{
+		Fields: []string{"missing", "empty", "literal"},
+		Values: []string{generator.NULL_VALUE, "", "null"},
+	}
+	for _, c := range []struct {
+		format     string
+		nullString string
+		expected   string
+	}{
+		{RECORD_FORMAT_JSON, DEFAULT_NULL_STRING, `{"missing":null,"empty":"","literal":"null"}`},
+		{RECORD_FORMAT_CSV, DEFAULT_NULL_STRING, `NULL,,null`},
+		{RECORD_FORMAT_CSV, "", `,,null`},
+	} {
+		f, err := NewRecordFormatter(c.format, c.nullString)
+		if err != nil {
+			t.Fatalf("failed to create %s formatter, %s", c.format, err)
+		}
+		if got, err := f.fmt(record); err != nil || got != c.expected {
+			t.Errorf("expected %s for %s records with null string %q but got %s (%v)", c.expected, c.format, c.nullString, got, err)
+		}
+	}
+	if _, err := NewRecordFormatter("xml", DEFAULT_NULL_STRING); err == nil {
+		t.Errorf("expected an error for an unknown record format")
+	}
+}
+
+func TestCSVRecordFormatter(t *testing.T) {
+	f, err := NewRecordFormatter(RECORD_FORMAT_CSV, DEFAULT_NULL_STRING)
+	if err != nil {
+		t.Fatalf("failed to create csv formatter, %s", err)
+	}
+	if header, ok, err := f.header([]string{"id", "fullName"}); err != nil || !ok || header != "id,fullName" {
+		t.Errorf("expected header 'id,fullName' but got '%s' (%v)", header, err)
+	}
+	record := &generators.Record{Fields: []string{"id", "fullName"}, Values: []string{"1", `Jean "Jo", Dupont`}}
+	if row, err := f.fmt(record); err != nil || row != `1,"Jean ""Jo"", Dupont"` {
+		t.Errorf("expected a quoted CSV row but got '%s' (%v)", row, err)
+	}
+	if _, ok, _ := (&JSONRecordFormatter{}).header([]string{"id"}); ok {
+		t.Errorf("expected JSON records to have no header")
+	}
+}
@@ -70,8 +70,16 @@ type SeedConfig struct {
 	Parser      string
 }
 
+// EntityConfig declares a record schema, overriding the DB entity of the
+// same name.
+type EntityConfig struct {
+	Name   string
+	Fields string
+}
+
 type Config struct {
-	Seeds []SeedConfig
+	Seeds    []SeedConfig
+	Entities []EntityConfig
 }
 
 var PERSON_LAST_NAME_EXTRACT_FILE string = "noms2008nat_txt.txt"
@@ -97,13 +105,42 @@ var defaultConfig *Config = &Config{
 			Encoding:    "Windows 1252",
 			Locale:      "fr-FR",
 			Parser:      "csv(skip_header,delim=\\t,column=0)",
+		}, {
+			Type:      SeedTypeRemote,
+			Name:      "[fr] misc.word",
+			PropTable: "misc",
+			PropType:  "word",
+			Url:       "https://raw.githubusercontent.com/hermitdave/FrequencyWords/master/content/2018/fr/fr_50k.txt",
+			Encoding:  "utf-8",
+			Locale:    "fr-FR",
+			Parser:    "csv(delim=' ',column=0)",
+		}, {
+			Type:      SeedTypeRemote,
+			Name:      "[es] misc.word",
+			PropTable: "misc",
+			PropType:  "word",
+			Url:       "https://raw.githubusercontent.com/hermitdave/FrequencyWords/master/content/2018/es/es_50k.txt",
+			Encoding:  "utf-8",
+			Locale:    "es-ES",
+			Parser:    "csv(delim=' ',column=0)",
+		}, {
+			Type:      SeedTypeRemote,
+			Name:      "[en] misc.word",
+			PropTable: "misc",
+			PropType:  "word",
+			Url:       "https://raw.githubusercontent.com/hermitdave/FrequencyWords/master/content/2018/en/en_50k.txt",
+			Encoding:  "utf-8",
+			Locale:    "en-US",
+			Parser:    "csv(delim=' ',column=0)",
 		},
 	},
+	Entities: []EntityConfig{},
 }
 
-func New(seeds []SeedConfig) *Config {
+func New(seeds []SeedConfig, entities []EntityConfig) *Config {
 	return &Config{
-		Seeds: seeds,
+		Seeds:    seeds,
+		Entities: entities,
 	}
 }
 
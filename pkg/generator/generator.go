@@ -1,17 +1,76 @@
 package generator
 
+import (
+	"hash/fnv"
+	"math/rand/v2"
+	"time"
+)
+
+// SEEDED_NOW is the clock of seeded runs, so that time-relative generators
+// stay reproducible.
+var SEEDED_NOW = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
 type GeneratorOptions struct {
 	OnlyUniqueValues     bool
 	MaximumUniqueRetries int
+
+	// RandSeed is set on reproducible runs, see SetRandSeed
+	RandSeed *uint64
+	// Rand is the source generators draw from
+	Rand *rand.Rand
+	// Now is the clock of time-relative generators
+	Now func() time.Time
 }
 
 func NewGeneratorOptions() *GeneratorOptions {
 	return &GeneratorOptions{
 		OnlyUniqueValues:     false,
 		MaximumUniqueRetries: 20,
+		RandSeed:             nil,
+		Rand:                 rand.New(runtimeSource{}),
+		Now:                  time.Now,
 	}
 }
 
+// runtimeSource draws from the randomly seeded, goroutine-safe top-level
+// source of math/rand/v2.
+type runtimeSource struct{}
+
+func (runtimeSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+// SetRandSeed makes runs reproducible: generators draw from a PCG seeded
+// with seed and the clock is pinned to SEEDED_NOW.
+func (o *GeneratorOptions) SetRandSeed(seed uint64) {
+	o.RandSeed = &seed
+	o.Rand = rand.New(rand.NewPCG(seed, 0))
+	o.Now = func() time.Time {
+		return SEEDED_NOW
+	}
+}
+
+// Fork returns a copy of the options with a source of their own. Seeded
+// sources derive from the seed and the stream name only, so that a stream
+// never depends on how the others are consumed.
+func (o *GeneratorOptions) Fork(stream string) *GeneratorOptions {
+	ret := *o
+	if o.RandSeed != nil {
+		h := fnv.New64a()
+		h.Write([]byte(stream))
+		ret.Rand = rand.New(rand.NewPCG(*o.RandSeed, h.Sum64()))
+	}
+	return &ret
+}
+
+// NULL_VALUE is returned by generators to denote a missing value, output
+// formatters rendering it with their own null representation.
+const NULL_VALUE = "\x00NULL\x00"
+
+func IsNull(v string) bool {
+	return v == NULL_VALUE
+}
+
 type Generator interface {
 	GetName() string
 	SetName(string)
@@ -0,0 +1,185 @@
+package generators
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/models"
+	"github.com/welschmorgan/datagen/pkg/utils"
+)
+
+const ADDRESS_GENERATOR_NAME = "address"
+
+const ADDRESS_DEFAULT_LOCALE = "fr-FR"
+const ADDRESS_MAX_STREET_NUMBER = 200
+
+var AddressOptions = []string{"format"}
+
+type AddressFormat struct {
+	Layout  string
+	Country string
+}
+
+// AddressFormats describes how each locale writes an address on one line, and
+// the ISO code of the locale's country.
+var AddressFormats = map[string]AddressFormat{
+	"fr-FR": {Layout: "{number} {street_type} {street_name}, {postal_code} {town}", Country: "FR"},
+	"en-UK": {Layout: "{number} {street_name} {street_type}, {town} {postal_code}", Country: "GB"},
+	"en-US": {Layout: "{number} {street_name} {street_type}, {town}, {state} {postal_code}", Country: "US"},
+	"es-ES": {Layout: "{street_type} {street_name}, {number}, {postal_code} {town}", Country: "ES"},
+}
+
+type Address struct {
+	Number     string `json:"number"`
+	StreetType string `json:"street_type"`
+	StreetName string `json:"street_name"`
+	PostalCode string `json:"postal_code"`
+	Town       string `json:"town"`
+	State      string `json:"state,omitempty"`
+	Country    string `json:"country"`
+}
+
+func (a *Address) Format(layout string) string {
+	return strings.NewReplacer(
+		"{number}", a.Number,
+		"{street_type}", a.StreetType,
+		"{street_name}", a.StreetName,
+		"{postal_code}", a.PostalCode,
+		"{town}", a.Town,
+		"{state}", a.State,
+		"{country}", a.Country,
+	).Replace(layout)
+}
+
+type AddressGenerator struct {
+	*CacheGenerator
+
+	db     *sql.DB
+	locale string
+	asJSON bool
+
+	once        sync.Once
+	loadErr     error
+	towns       []*models.Location
+	streetTypes []*models.Location
+	streetNames []*models.Location
+	states      map[string]string
+	country     string
+}
+
+// ParseAddress parses 'locale;format=line|json'.
+func ParseAddress(params ...any) (locale string, asJSON bool, err error) {
+	spec, opts, err := ParseSpecOptions(AddressOptions, params...)
+	if err != nil {
+		return "", false, err
+	}
+	locale = spec
+	if len(locale) == 0 {
+		locale = ADDRESS_DEFAULT_LOCALE
+	}
+	if _, ok := AddressFormats[locale]; !ok {
+		return "", false, fmt.Errorf("unsupported address locale '%s'", locale)
+	}
+	switch strings.ToLower(opts["format"]) {
+	case "", "line":
+	case "json":
+		asJSON = true
+	default:
+		return "", false, fmt.Errorf("invalid address format '%s', expected 'line' or 'json'", opts["format"])
+	}
+	return locale, asJSON, nil
+}
+
+func NewAddressGenerator(options *generator.GeneratorOptions, db *sql.DB, locale string, asJSON bool) *AddressGenerator {
+	ret := &AddressGenerator{
+		db:     db,
+		locale: locale,
+		asJSON: asJSON,
+	}
+	ret.CacheGenerator = NewCacheGenerator(options, ADDRESS_GENERATOR_NAME, ret.next)
+	return ret
+}
+
+func (g *AddressGenerator) load() error {
+	var err error
+	for typ, dst := range map[string]*[]*models.Location{"town": &g.towns, "street-type": &g.streetTypes, "street-name": &g.streetNames} {
+		if *dst, err = models.LoadLocations(g.db, typ, &g.locale); err != nil {
+			return err
+		}
+		if len(*dst) == 0 {
+			return fmt.Errorf("invalid address generator, no '%s' found for locale '%s' in %s", typ, g.locale, models.LOCATION_TABLE)
+		}
+	}
+	// states are only loaded by layouts using them, as 'town-state' props
+	// whose code is the state of the town
+	g.states = map[string]string{}
+	withStates := strings.Contains(AddressFormats[g.locale].Layout, "{state}")
+	if withStates {
+		states, err := models.LoadLocations(g.db, "town-state", &g.locale)
+		if err != nil {
+			return err
+		}
+		for _, s := range states {
+			if s.Code != nil {
+				g.states[s.Value] = *s.Code
+			}
+		}
+	}
+	for _, town := range g.towns {
+		if town.Code == nil {
+			return fmt.Errorf("invalid address generator, town '%s' has no postal code", town.Value)
+		}
+		if _, ok := g.states[town.Value]; !ok && withStates {
+			return fmt.Errorf("invalid address generator, town '%s' has no state", town.Value)
+		}
+	}
+	countries, err := models.LoadLocations(g.db, "country", &g.locale)
+	if err != nil {
+		return err
+	}
+	code := AddressFormats[g.locale].Country
+	if found := utils.Find(countries, func(c *models.Location) bool { return c.Code != nil && *c.Code == code }); found != nil {
+		g.country = (*found).Value
+	}
+	return nil
+}
+
+// Rand returns an address whose postal code and state match its town.
+func (g *AddressGenerator) Rand() (*Address, error) {
+	g.once.Do(func() {
+		g.loadErr = g.load()
+	})
+	if g.loadErr != nil {
+		return nil, g.loadErr
+	}
+	town := g.towns[g.options.Rand.IntN(len(g.towns))]
+	return &Address{
+		Number:     strconv.Itoa(1 + g.options.Rand.IntN(ADDRESS_MAX_STREET_NUMBER)),
+		StreetType: g.streetTypes[g.options.Rand.IntN(len(g.streetTypes))].Value,
+		StreetName: g.streetNames[g.options.Rand.IntN(len(g.streetNames))].Value,
+		PostalCode: *town.Code,
+		Town:       town.Value,
+		State:      g.states[town.Value],
+		Country:    g.country,
+	}, nil
+}
+
+func (g *AddressGenerator) next() (string, error) {
+	addr, err := g.Rand()
+	if err != nil {
+		return "", err
+	}
+	if g.asJSON {
+		data, err := json.Marshal(addr)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	return addr.Format(AddressFormats[g.locale].Layout), nil
+}
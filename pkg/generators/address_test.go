@@ -0,0 +1,102 @@
+package generators_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+	"github.com/welschmorgan/datagen/pkg/models"
+)
+
+// seededDB returns a DB seeded with the default schema.
+func seededDB(t *testing.T) *sql.DB {
+	schema, err := os.ReadFile(filepath.Join("..", "..", "assets", "seed.sql"))
+	if err != nil {
+		t.Fatalf("failed to read seed schema, %s", err)
+	}
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "datagen.db"))
+	if err != nil {
+		t.Fatalf("failed to open DB, %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	if _, err := db.Exec(string(schema)); err != nil {
+		t.Fatalf("failed to seed DB, %s", err)
+	}
+	return db
+}
+
+func TestParseAddress(t *testing.T) {
+	locale, asJSON, err := generators.ParseAddress("address", "es-ES;format=json")
+	if err != nil {
+		t.Fatalf("failed to parse address settings, %s", err)
+	}
+	if locale != "es-ES" || !asJSON {
+		t.Errorf("invalid address settings, locale=%s json=%v", locale, asJSON)
+	}
+	if locale, _, _ := generators.ParseAddress("address"); locale != generators.ADDRESS_DEFAULT_LOCALE {
+		t.Errorf("expected default locale, got '%s'", locale)
+	}
+	for _, tpl := range []string{"xx-XX", "fr-FR;format=xml", "fr-FR;unknown=1"} {
+		if _, _, err := generators.ParseAddress("address", tpl); err == nil {
+			t.Errorf("expected an error for address template '%s'", tpl)
+		}
+	}
+}
+
+func TestAddressFormat(t *testing.T) {
+	addr := &generators.Address{Number: "12", StreetType: "rue", StreetName: "de la Paix", PostalCode: "75002", Town: "Paris"}
+	if got := addr.Format(generators.AddressFormats["fr-FR"].Layout); got != "12 rue de la Paix, 75002 Paris" {
+		t.Errorf("invalid french address '%s'", got)
+	}
+}
+
+func TestAddressGeneratorConsistency(t *testing.T) {
+	db := seededDB(t)
+	for locale := range generators.AddressFormats {
+		towns, err := models.LoadLocations(db, "town", &locale)
+		if err != nil {
+			t.Fatalf("failed to load %s towns, %s", locale, err)
+		}
+		states, err := models.LoadLocations(db, "town-state", &locale)
+		if err != nil {
+			t.Fatalf("failed to load %s states, %s", locale, err)
+		}
+		postalCodes, townStates := map[string]string{}, map[string]string{}
+		for _, town := range towns {
+			postalCodes[town.Value] = *town.Code
+		}
+		for _, state := range states {
+			townStates[state.Value] = *state.Code
+		}
+		g := generators.NewAddressGenerator(generator.NewGeneratorOptions(), db, locale, false)
+		for range 50 {
+			addr, err := g.Rand()
+			if err != nil {
+				t.Fatalf("failed to generate %s address, %s", locale, err)
+			}
+			if code, ok := postalCodes[addr.Town]; !ok || code != addr.PostalCode {
+				t.Errorf("expected %s postal code of '%s' to be '%s' but got '%s'", locale, addr.Town, code, addr.PostalCode)
+			}
+			if addr.State != townStates[addr.Town] {
+				t.Errorf("expected %s state of '%s' to be '%s' but got '%s'", locale, addr.Town, townStates[addr.Town], addr.State)
+			}
+			if locale == "en-US" && len(addr.State) != 2 {
+				t.Errorf("expected a state in US address %+v", addr)
+			}
+			if len(addr.Country) == 0 {
+				t.Errorf("expected a country in %s address %+v", locale, addr)
+			}
+		}
+	}
+}
+
+func TestAddressFormatWithState(t *testing.T) {
+	addr := &generators.Address{Number: "350", StreetType: "Avenue", StreetName: "Fifth", PostalCode: "10001", Town: "New York", State: "NY"}
+	if got := addr.Format(generators.AddressFormats["en-US"].Layout); got != "350 Fifth Avenue, New York, NY 10001" {
+		t.Errorf("invalid US address '%s'", got)
+	}
+}
@@ -37,7 +37,23 @@ func AllocateGeneratorPattern(options *generator.GeneratorOptions, params ...any
 	if err != nil {
 		return nil, err
 	}
-	return NewPatternGenerator(options, pattern), nil
+	parts, err := ParseLegacyPatternParts(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return NewPatternGenerator(options, PATTERN_GENERATOR_NAME, pattern, parts), nil
+}
+
+func AllocateGeneratorPatternV2(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	pattern, err := ParsePattern(params...)
+	if err != nil {
+		return nil, err
+	}
+	parts, err := ParsePatternParts(pattern)
+	if err != nil {
+		return nil, err
+	}
+	return NewPatternGenerator(options, PATTERN_V2_GENERATOR_NAME, pattern, parts), nil
 }
 
 func AllocateGeneratorUnion(db *sql.DB, resGetter func(name string) generator.Generator) GeneratorAllocator {
@@ -50,6 +66,26 @@ func AllocateGeneratorUnion(db *sql.DB, resGetter func(name string) generator.Ge
 	}
 }
 
+func AllocateGeneratorEmail(db *sql.DB, resGetter func(name string) generator.Generator) GeneratorAllocator {
+	return func(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+		settings, err := ParseEmail(params...)
+		if err != nil {
+			return nil, err
+		}
+		return NewEmailGenerator(db, options, settings, resGetter), nil
+	}
+}
+
+func AllocateGeneratorTemplate(resGetter func(name string) generator.Generator) GeneratorAllocator {
+	return func(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+		template, parts, err := ParseTemplate(params...)
+		if err != nil {
+			return nil, err
+		}
+		return NewTemplateGenerator(options, template, parts, resGetter), nil
+	}
+}
+
 func AllocateGeneratorIntRange(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
 	r, err := ParseRangeArgs(params...)
 	if err != nil {
@@ -58,6 +94,111 @@ func AllocateGeneratorIntRange(options *generator.GeneratorOptions, params ...an
 	return NewIntRangeGenerator(options, r), nil
 }
 
+func AllocateGeneratorSequence(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	sequence, err := ParseSequence(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewSequenceGenerator(options, sequence), nil
+}
+
+func AllocateGeneratorFloatRange(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	r, err := ParseFloatRangeArgs(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewFloatRangeGenerator(options, r), nil
+}
+
+func AllocateGeneratorDatetime(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	r, err := ParseDatetimeRange(options.Now(), params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewDatetimeGenerator(options, r), nil
+}
+
+func AllocateGeneratorUUID(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	version, err := ParseUUIDVersion(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewUUIDGenerator(options, version), nil
+}
+
+func AllocateGeneratorULID(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	if _, _, err := ParseSpecOptions([]string{}, params...); err != nil {
+		return nil, err
+	}
+	return NewULIDGenerator(options), nil
+}
+
+func AllocateGeneratorSnowflake(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	epoch, node, err := ParseSnowflake(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewSnowflakeGenerator(options, epoch, node), nil
+}
+
+func AllocateGeneratorRegex(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	expr, re, maxRepeat, err := ParseRegex(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewRegexGenerator(options, expr, re, maxRepeat), nil
+}
+
+func AllocateGeneratorMarkov(db *sql.DB) GeneratorAllocator {
+	return func(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+		settings, err := ParseMarkov(params...)
+		if err != nil {
+			return nil, err
+		}
+		return NewMarkovGenerator(options, db, settings), nil
+	}
+}
+
+func AllocateGeneratorText(db *sql.DB) GeneratorAllocator {
+	return func(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+		settings, err := ParseText(params...)
+		if err != nil {
+			return nil, err
+		}
+		return NewTextGenerator(options, db, settings), nil
+	}
+}
+
+func AllocateGeneratorIBAN(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	countries, grouped, err := ParseIBAN(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewIBANGenerator(options, countries, grouped), nil
+}
+
+func AllocateGeneratorCreditCard(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	networks, grouped, err := ParseCreditCard(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewCreditCardGenerator(options, networks, grouped), nil
+}
+
+func AllocateGeneratorNationalId(name string) GeneratorAllocator {
+	return func(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+		_, opts, err := ParseSpecOptions(NationalIdOptions, params...)
+		if err != nil {
+			return nil, err
+		}
+		grouped, err := parseGroupedFormat(opts)
+		if err != nil {
+			return nil, err
+		}
+		return NewNationalIdGenerator(options, name, grouped)
+	}
+}
+
 func AllocateGeneratorRandomDB(db *sql.DB) GeneratorAllocator {
 	return func(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
 		expectedArgs := 3
@@ -70,12 +211,142 @@ func AllocateGeneratorRandomDB(db *sql.DB) GeneratorAllocator {
 			return nil, err
 		}
 		tableName := args[1]
-		parts := strings.Split(args[2], "=")
+		// the filter may be followed by ';locale=fr-FR'
+		filter, localeOpt, hasLocale := strings.Cut(args[2], ";")
+		locale := ""
+		if hasLocale {
+			key, value, _ := strings.Cut(localeOpt, "=")
+			if strings.TrimSpace(key) != "locale" || len(strings.TrimSpace(value)) == 0 {
+				return nil, fmt.Errorf("invalid arguments to RandomDBRowGenerator, expected 'locale=name' after the filter but got '%s'", localeOpt)
+			}
+			locale = strings.TrimSpace(value)
+		}
+		parts := strings.Split(filter, "=")
 		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid arguments to RandomDBRowGenerator, tableFilter is invalid. Expected 'column=value' but got '%s'", args[1])
+			return nil, fmt.Errorf("invalid arguments to RandomDBRowGenerator, tableFilter is invalid. Expected 'column=value' but got '%s'", filter)
 		}
 		tableFilterKey := parts[0]
 		tableFilterValue := parts[1]
-		return NewRandomDBRowGenerator(options, db, tableName, tableFilterKey, tableFilterValue)
+		return NewRandomDBRowGenerator(options, db, tableName, tableFilterKey, tableFilterValue, locale)
+	}
+}
+
+func AllocateGeneratorAddress(db *sql.DB) GeneratorAllocator {
+	return func(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+		locale, asJSON, err := ParseAddress(params...)
+		if err != nil {
+			return nil, err
+		}
+		return NewAddressGenerator(options, db, locale, asJSON), nil
+	}
+}
+
+func AllocateGeneratorGeo(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	area, precision, format, err := ParseGeo(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewGeoGenerator(options, area, precision, format), nil
+}
+
+func AllocateGeneratorIPv4(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	prefix, err := ParsePrefix(4, IPV4_DEFAULT_PREFIX, params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewIPGenerator(options, IPV4_GENERATOR_NAME, prefix), nil
+}
+
+func AllocateGeneratorIPv6(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	prefix, err := ParsePrefix(6, IPV6_DEFAULT_PREFIX, params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewIPGenerator(options, IPV6_GENERATOR_NAME, prefix), nil
+}
+
+func AllocateGeneratorMAC(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	oui, format, err := ParseMAC(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewMACGenerator(options, oui, format), nil
+}
+
+func AllocateGeneratorHostname(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	settings, err := ParseHostname(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewHostnameGenerator(options, settings), nil
+}
+
+func AllocateGeneratorURL(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	settings, err := ParseURL(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewURLGenerator(options, settings), nil
+}
+
+func AllocateGeneratorNullable(resGetter func(name string) generator.Generator) GeneratorAllocator {
+	return func(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+		resource, ratio, err := ParseNullable(params...)
+		if err != nil {
+			return nil, err
+		}
+		return NewNullableGenerator(options, resource, ratio, resGetter), nil
+	}
+}
+
+func AllocateGeneratorBool(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	values, ratio, err := ParseBool(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewBoolGenerator(options, values, ratio), nil
+}
+
+func AllocateGeneratorFilter(resGetter func(name string) generator.Generator) GeneratorAllocator {
+	return func(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+		resource, filters, err := ParseFilterArgs(params...)
+		if err != nil {
+			return nil, err
+		}
+		return NewFilterGenerator(options, resource, filters, resGetter), nil
+	}
+}
+
+func AllocateGeneratorSecret(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	settings, err := ParseSecret(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewSecretGenerator(options, settings), nil
+}
+
+func AllocateGeneratorPhone(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	settings, err := ParsePhone(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewPhoneGenerator(options, settings), nil
+}
+
+func AllocateGeneratorMoney(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+	settings, err := ParseMoney(params...)
+	if err != nil {
+		return nil, err
+	}
+	return NewMoneyGenerator(options, settings), nil
+}
+
+func AllocateGeneratorImage(resGetter func(name string) generator.Generator) GeneratorAllocator {
+	return func(options *generator.GeneratorOptions, params ...any) (generator.Generator, error) {
+		settings, err := ParseImage(params...)
+		if err != nil {
+			return nil, err
+		}
+		return NewImageGenerator(options, settings, resGetter), nil
 	}
 }
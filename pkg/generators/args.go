@@ -2,6 +2,7 @@ package generators
 
 import (
 	"fmt"
+	"math"
 	"math/rand/v2"
 	"regexp"
 	"slices"
@@ -10,17 +11,57 @@ import (
 )
 
 func ParsePattern(params ...any) (pattern string, err error) {
-	if len(params) != 2 {
+	if len(params) < 2 {
 		return "", fmt.Errorf("invalid arguments, expected ['generator_name', 'pattern'] but got %v", params)
 	}
-	return params[1].(string), nil
+	args, err := ParseStrings(len(params), params...)
+	if err != nil {
+		return "", err
+	}
+	// placeholders such as '{hex:8}' use the ':' argument separator
+	return strings.Join(args[1:], ":"), nil
 }
 
-func ParseUnion(params ...any) (pattern []string, err error) {
-	if len(params) != 2 {
+type UnionVariant struct {
+	Name   string
+	Weight int64
+}
+
+// ParseUnion parses variants such as 'a|b' or 'a:2|b:40', unweighted variants
+// have a weight of 1.
+func ParseUnion(params ...any) (variants []UnionVariant, err error) {
+	if len(params) < 2 {
 		return nil, fmt.Errorf("invalid arguments, expected ['generator_name', 'pattern'] but got %v", params)
 	}
-	return strings.Split(params[1].(string), "|"), nil
+	args, err := ParseStrings(len(params), params...)
+	if err != nil {
+		return nil, err
+	}
+	// weights use the ':' argument separator, so rebuild the whole pattern
+	pattern := strings.Join(args[1:], ":")
+	total := int64(0)
+	for _, part := range strings.Split(pattern, "|") {
+		variant := UnionVariant{Name: strings.TrimSpace(part), Weight: 1}
+		if pos := strings.LastIndex(part, ":"); pos != -1 {
+			variant.Name = strings.TrimSpace(part[:pos])
+			weight := strings.TrimSpace(part[pos+1:])
+			if variant.Weight, err = strconv.ParseInt(weight, 10, 64); err != nil {
+				return nil, fmt.Errorf("invalid weight '%s' for union variant '%s', %s", weight, variant.Name, err)
+			}
+			if variant.Weight < 0 {
+				return nil, fmt.Errorf("invalid weight '%s' for union variant '%s', expected a positive number", weight, variant.Name)
+			}
+		}
+		if len(variant.Name) == 0 {
+			return nil, fmt.Errorf("invalid union '%s', empty variant", pattern)
+		}
+		variants = append(variants, variant)
+		total += variant.Weight
+	}
+	if total <= 0 {
+		return nil, fmt.Errorf("invalid union '%s', total weight of variants is %d", pattern, total)
+	}
+	return variants, nil
 }
 
 type Range[T any] interface {
@@ -30,8 +71,8 @@ type Range[T any] interface {
 
 	Exclusions() []T
 
-	Rand() T
-	RandPadded() string
+	Rand(rng *rand.Rand) T
+	RandPadded(rng *rand.Rand) string
 }
 
 var PatternRange = regexp.MustCompile(`(\d+\.\.\d+[\!\d\|]*|[\d\|]+)`)
@@ -43,6 +84,10 @@ type IntRange struct {
 	max int64
 
 	exclude []int64
+	dist    Distribution
+
+	// inclusive ranges may yield max, as in pattern placeholders
+	inclusive bool
 
 	minLen int
 	maxLen int
@@ -74,20 +119,40 @@ func (r *IntRange) String() string {
 			excludes = fmt.Sprintf("%s%d", excludes, x)
 		}
 	}
-	return fmt.Sprintf("%d..%d%s", r.min, r.max, excludes)
+	dist := ""
+	if r.dist != nil {
+		dist = fmt.Sprintf("~%s", r.dist)
+	}
+	return fmt.Sprintf("%d..%d%s%s", r.min, r.max, excludes, dist)
 }
-func (r *IntRange) Rand() int64 {
+
+func (r *IntRange) Distribution() Distribution {
+	return r.dist
+}
+
+func (r *IntRange) Rand(rng *rand.Rand) int64 {
+	hi := r.max - 1
+	if r.inclusive {
+		hi = r.max
+	}
 	var val int64
 	for {
-		val = r.min + rand.Int64N(r.max-r.min)
+		if r.dist != nil {
+			// hi+1 is only reached by the upper bound itself, which is rejected
+			if val = int64(math.Floor(r.dist.Sample(rng, float64(r.min), float64(hi+1)))); val > hi {
+				continue
+			}
+		} else {
+			val = r.min + rng.Int64N(hi-r.min+1)
+		}
 		if !slices.Contains(r.exclude, val) {
 			break
 		}
 	}
 	return val
 }
-func (r *IntRange) RandPadded() string {
-	val := fmt.Sprintf("%d", r.Rand())
+func (r *IntRange) RandPadded(rng *rand.Rand) string {
+	val := fmt.Sprintf("%d", r.Rand(rng))
 	pad := ""
 	if len(val) < r.minLen {
 		pad = strings.Repeat("0", r.minLen-len(val))
@@ -138,13 +203,13 @@ func (r *DiscreteValues) String() string {
 	return strings.Join(items, "|")
 }
 
-func (r *DiscreteValues) Rand() int64 {
-	id := rand.IntN(len(r.values))
+func (r *DiscreteValues) Rand(rng *rand.Rand) int64 {
+	id := rng.IntN(len(r.values))
 	return r.values[id]
 }
 
-func (r *DiscreteValues) RandPadded() string {
-	id := rand.IntN(len(r.values))
+func (r *DiscreteValues) RandPadded(rng *rand.Rand) string {
+	id := rng.IntN(len(r.values))
 	val := fmt.Sprintf("%d", r.values[id])
 	pad := ""
 	if len(val) < r.sizes[id] {
@@ -169,7 +234,21 @@ func ParseRangeArgs(params ...any) (r Range[int64], err error) {
 	return ParseRange(expr)
 }
 
+// ParseRange parses 'min..max!exclusions~distribution' or discrete values
+// such as '1|2|3', the distribution suffix being optional. Distributions are
+// truncated to the range, those mostly lying outside of it being rejected.
 func ParseRange(s string) (Range[int64], error) {
+	var dist Distribution
+	if pos := strings.Index(s, "~"); pos != -1 {
+		var err error
+		if dist, err = ParseDistribution(s[pos+1:]); err != nil {
+			return nil, err
+		}
+		if !strings.Contains(s[:pos], "..") {
+			return nil, fmt.Errorf("invalid range '%s', distributions only apply to 'min..max' ranges", s)
+		}
+		s = s[:pos]
+	}
 	discreteValues := func(s string) (*DiscreteValues, error) {
 		parts := strings.Split(s, "|")
 		ret := &DiscreteValues{
@@ -215,11 +294,17 @@ func ParseRange(s string) (Range[int64], error) {
 		if err != nil {
 			return nil, err
 		}
+		if dist != nil {
+			if err := CheckDistributionMass(dist, float64(min), float64(max)); err != nil {
+				return nil, err
+			}
+		}
 		ret := &IntRange{
 			min: min,
 			max: max,
 
 			exclude: exclude,
+			dist:    dist,
 
 			minLen: sizes[0],
 			maxLen: sizes[1],
@@ -229,6 +314,187 @@ func ParseRange(s string) (Range[int64], error) {
 	return discreteValues(s)
 }
 
+// FloatRange is an inclusive decimal range, values keep the precision declared
+// by the most precise bound ('0.00..9.9' yields 2 decimals).
+type FloatRange struct {
+	Range[float64]
+
+	min int64
+	max int64
+
+	exclude []int64
+	dist    Distribution
+
+	precision int
+	scale     int64
+	minLen    int
+}
+
+func (r *FloatRange) Exclusions() []float64 {
+	ret := []float64{}
+	for _, x := range r.exclude {
+		ret = append(ret, float64(x)/float64(r.scale))
+	}
+	return ret
+}
+
+func (r *FloatRange) Bounds() (float64, float64) {
+	return float64(r.min) / float64(r.scale), float64(r.max) / float64(r.scale)
+}
+
+func (r *FloatRange) Min() float64 {
+	min, _ := r.Bounds()
+	return min
+}
+
+func (r *FloatRange) Max() float64 {
+	_, max := r.Bounds()
+	return max
+}
+
+func (r *FloatRange) Precision() int {
+	return r.precision
+}
+
+func (r *FloatRange) String() string {
+	excludes := []string{}
+	for _, x := range r.exclude {
+		excludes = append(excludes, r.format(x, 0))
+	}
+	ret := fmt.Sprintf("%s..%s", r.format(r.min, 0), r.format(r.max, 0))
+	if len(excludes) > 0 {
+		ret = fmt.Sprintf("%s!%s", ret, strings.Join(excludes, "|"))
+	}
+	if r.dist != nil {
+		ret = fmt.Sprintf("%s~%s", ret, r.dist)
+	}
+	return ret
+}
+
+func (r *FloatRange) Distribution() Distribution {
+	return r.dist
+}
+
+func (r *FloatRange) randScaled(rng *rand.Rand) int64 {
+	var val int64
+	for {
+		if r.dist != nil {
+			lo, hi := r.Bounds()
+			val = int64(math.Round(r.dist.Sample(rng, lo, hi) * float64(r.scale)))
+			val = max(r.min, min(val, r.max))
+		} else {
+			val = r.min + rng.Int64N(r.max-r.min+1)
+		}
+		if !slices.Contains(r.exclude, val) {
+			break
+		}
+	}
+	return val
+}
+
+func (r *FloatRange) Rand(rng *rand.Rand) float64 {
+	return float64(r.randScaled(rng)) / float64(r.scale)
+}
+
+func (r *FloatRange) RandPadded(rng *rand.Rand) string {
+	return r.format(r.randScaled(rng), r.minLen)
+}
+
+// format renders a scaled value with the range precision, zero-padding the
+// integer part to intLen digits.
+func (r *FloatRange) format(v int64, intLen int) string {
+	sign := ""
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	ret := fmt.Sprintf("%s%0*d", sign, intLen, v/r.scale)
+	if r.precision > 0 {
+		ret = fmt.Sprintf("%s.%0*d", ret, r.precision, v%r.scale)
+	}
+	return ret
+}
+
+func ParseFloatRangeArgs(params ...any) (r *FloatRange, err error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("invalid arguments, expected ['generator_name', 'min..max'] but got %v", params)
+	}
+	args, err := ParseStrings(len(params), params...)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFloatRange(args[1])
+}
+
+func ParseFloatRange(s string) (*FloatRange, error) {
+	var dist Distribution
+	if pos := strings.Index(s, "~"); pos != -1 {
+		var err error
+		if dist, err = ParseDistribution(s[pos+1:]); err != nil {
+			return nil, err
+		}
+		s = s[:pos]
+	}
+	parts := strings.Split(s, "..")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid range, expected 'min..max' but got '%s'", s)
+	}
+	excludeParts := []string{}
+	if pos := strings.Index(parts[1], "!"); pos != -1 {
+		excludeParts = strings.Split(parts[1][pos+1:], "|")
+		parts[1] = parts[1][:pos]
+	}
+	decimals := func(v string) (intLen int, precision int) {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "-")
+		if pos := strings.Index(v, "."); pos != -1 {
+			return pos, len(v) - pos - 1
+		}
+		return len(v), 0
+	}
+	var err error
+	ret := &FloatRange{exclude: []int64{}, dist: dist}
+	for _, v := range append(slices.Clone(parts), excludeParts...) {
+		_, precision := decimals(v)
+		ret.precision = max(ret.precision, precision)
+	}
+	ret.minLen, _ = decimals(parts[0])
+	ret.scale = 1
+	for range ret.precision {
+		ret.scale *= 10
+	}
+	scaled := func(v string) (int64, error) {
+		v = strings.TrimSpace(v)
+		f, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid decimal '%s', %s", v, err)
+		}
+		return int64(math.Round(f * float64(ret.scale))), nil
+	}
+	if ret.min, err = scaled(parts[0]); err != nil {
+		return nil, err
+	}
+	if ret.max, err = scaled(parts[1]); err != nil {
+		return nil, err
+	}
+	if ret.min > ret.max {
+		return nil, fmt.Errorf("invalid range '%s', min is greater than max", s)
+	}
+	for _, x := range excludeParts {
+		v, err := scaled(x)
+		if err != nil {
+			return nil, err
+		}
+		ret.exclude = append(ret.exclude, v)
+	}
+	if dist != nil {
+		lo, hi := ret.Bounds()
+		if err := CheckDistributionMass(dist, lo, hi); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
+}
+
 func ParseStrings(minRequired int, params ...any) (args []string, err error) {
 	if len(params) != minRequired {
 		return nil, fmt.Errorf("invalid arguments: %v", params)
@@ -248,3 +514,38 @@ func ParseStrings(minRequired int, params ...any) (args []string, err error) {
 	}
 	return ret, nil
 }
+
+// ParseSpecOptions splits a template such as 'spec;key=value;key=value' into
+// its spec and options. Arguments are joined back with ':' as layouts and
+// timestamps may contain it. Options not listed in known are rejected.
+func ParseSpecOptions(known []string, params ...any) (spec string, opts map[string]string, err error) {
+	if len(params) == 1 {
+		// resources without template
+		return "", map[string]string{}, nil
+	} else if len(params) < 1 {
+		return "", nil, fmt.Errorf("invalid arguments, expected ['generator_name', 'spec;key=value'] but got %v", params)
+	}
+	args, err := ParseStrings(len(params), params...)
+	if err != nil {
+		return "", nil, err
+	}
+	parts := strings.Split(strings.Join(args[1:], ":"), ";")
+	spec = strings.TrimSpace(parts[0])
+	opts = map[string]string{}
+	for _, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		key := strings.ToLower(strings.TrimSpace(kv[0]))
+		if !slices.Contains(known, key) {
+			return "", nil, fmt.Errorf("unknown option '%s', expected one of %v", key, known)
+		}
+		opts[key] = ""
+		if len(kv) > 1 {
+			opts[key] = strings.TrimSpace(kv[1])
+		}
+	}
+	return spec, opts, nil
+}
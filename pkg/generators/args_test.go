@@ -1,6 +1,8 @@
 package generators_test
 
 import (
+	"math"
+	"math/rand/v2"
 	"slices"
 	"testing"
 
@@ -47,3 +49,134 @@ func TestParseDiscreteValues(t *testing.T) {
 		t.Errorf("invalid bounds, expected '%d..%d' but got '%d..%d'", 1, 3, min, max)
 	}
 }
+
+func TestParseUnion(t *testing.T) {
+	variants, err := generators.ParseUnion("union", "a", "2|b|c", "40")
+	if err != nil {
+		t.Fatalf("failed to parse union, %s", err)
+	}
+	expected := []generators.UnionVariant{{Name: "a", Weight: 2}, {Name: "b", Weight: 1}, {Name: "c", Weight: 40}}
+	if !slices.Equal(variants, expected) {
+		t.Errorf("invalid union variants, expected %v but got %v", expected, variants)
+	}
+	for _, tpl := range []string{"a:0|b:0", "a:0", "a:-1|b"} {
+		if _, err := generators.ParseUnion("union", tpl); err == nil {
+			t.Errorf("expected an error for union '%s'", tpl)
+		}
+	}
+}
+
+func TestParseFloatRange(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	expr := "-10.5..999.99!0.00|1.5"
+	rng, err := generators.ParseFloatRange(expr)
+	if err != nil {
+		t.Fatalf("failed to parse FloatRange from '%s', %s", expr, err)
+	}
+	min, max := rng.Bounds()
+	if min != -10.5 || max != 999.99 {
+		t.Errorf("invalid bounds, expected '%f..%f' but got '%f..%f'", -10.5, 999.99, min, max)
+	}
+	if rng.Precision() != 2 {
+		t.Errorf("invalid precision, expected %d but got %d", 2, rng.Precision())
+	}
+	expected := []float64{0, 1.5}
+	if slices.Compare(rng.Exclusions(), expected) != 0 {
+		t.Errorf("invalid exclusions for FloatRange, expected %v but got %v", expected, rng.Exclusions())
+	}
+	if rng.String() != "-10.50..999.99!0.00|1.50" {
+		t.Errorf("invalid string representation '%s'", rng.String())
+	}
+	for range 100 {
+		v := rng.Rand(src)
+		if v < min || v > max {
+			t.Fatalf("value %f out of bounds", v)
+		}
+	}
+}
+
+func TestParseIntRangeWithDistribution(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	expr := "0..100!50~normal(50,5)"
+	rng, err := generators.ParseRange(expr)
+	if err != nil {
+		t.Fatalf("failed to parse IntRange from '%s', %s", expr, err)
+	}
+	if rng.(*generators.IntRange).Distribution() == nil {
+		t.Fatalf("missing distribution for '%s'", expr)
+	}
+	sum := int64(0)
+	for range 1000 {
+		v := rng.Rand(src)
+		if v < 0 || v >= 100 || v == 50 {
+			t.Fatalf("invalid value %d for '%s'", v, expr)
+		}
+		sum += v
+	}
+	if mean := sum / 1000; mean < 45 || mean > 55 {
+		t.Errorf("invalid mean %d for '%s'", mean, expr)
+	}
+}
+
+func TestIntRangeDistributionTails(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	counts := func(expr string) map[int64]int {
+		rng, err := generators.ParseRange(expr)
+		if err != nil {
+			t.Fatalf("failed to parse IntRange from '%s', %s", expr, err)
+		}
+		ret := map[int64]int{}
+		for range 20000 {
+			v := rng.Rand(src)
+			if v < 0 || v >= 10 {
+				t.Fatalf("invalid value %d for '%s'", v, expr)
+			}
+			ret[v]++
+		}
+		return ret
+	}
+	// values beyond the range must not pile up on the last value
+	if c := counts("0..10~zipf(1.5,1)"); c[9] >= c[8] {
+		t.Errorf("expected zipf frequencies to decrease, got %d for 8 and %d for 9", c[8], c[9])
+	}
+	// clipping would yield about 84% of 9s, resampling about 49%
+	if c := counts("0..10~normal(11,2)"); c[9] > 20000*65/100 {
+		t.Errorf("expected values out of the range to be resampled, got %d values of 9", c[9])
+	}
+}
+
+func TestParseDistributionErrors(t *testing.T) {
+	for _, expr := range []string{"1|2~normal(1,2)", "0..10~normal(1)", "0..10~unknown(1)", "0..10~zipf(1,1)", "0..10~histogram(0,0)", "0..10~normal(5,0)", "0..10~lognormal(1,-1)", "0..100~normal(5000,10)", "0..10~poisson(100)"} {
+		if _, err := generators.ParseRange(expr); err == nil {
+			t.Errorf("expected an error for range '%s'", expr)
+		}
+	}
+	for _, expr := range []string{"0.0..1.0~normal(50,1)", "0.0..0.1~exponential(0.01)"} {
+		if _, err := generators.ParseFloatRange(expr); err == nil {
+			t.Errorf("expected an error for range '%s'", expr)
+		}
+	}
+}
+
+func TestDistributionMass(t *testing.T) {
+	for _, c := range []struct {
+		dist     string
+		min, max float64
+		expected float64
+	}{
+		{"normal(0,1)", -1, 1, 0.6827},
+		{"lognormal(0,1)", 0, 1, 0.5},
+		{"exponential(1)", 0, 1, 0.6321},
+		{"poisson(2)", 0, 1, 0.4060},
+		{"poisson(1000)", 0, 1000, 0.5063},
+		{"zipf(2,1)", 0, 10, 1},
+	} {
+		dist, err := generators.ParseDistribution(c.dist)
+		if err != nil {
+			t.Fatalf("failed to parse distribution '%s', %s", c.dist, err)
+		}
+		if mass := dist.Mass(c.min, c.max); math.Abs(mass-c.expected) > 0.001 {
+			t.Errorf("expected a mass of %g for %s on %g..%g but got %g", c.expected, c.dist, c.min, c.max, mass)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package generators
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const BOOL_GENERATOR_NAME = "bool"
+
+const BOOL_DEFAULT_RATIO = 0.5
+
+var BoolOptions = []string{"ratio"}
+
+// ParseBool parses 'true|false;ratio=0.8', the optional spec naming the true
+// and false values, ratio being the fraction of true values.
+func ParseBool(params ...any) (values [2]string, ratio float64, err error) {
+	spec, opts, err := ParseSpecOptions(BoolOptions, params...)
+	if err != nil {
+		return values, 0, err
+	}
+	values = [2]string{"true", "false"}
+	if len(spec) > 0 {
+		parts := strings.Split(spec, "|")
+		if len(parts) != 2 {
+			return values, 0, fmt.Errorf("invalid bool values '%s', expected 'true_value|false_value'", spec)
+		}
+		values = [2]string{parts[0], parts[1]}
+	}
+	if ratio, err = ParseRatio(opts["ratio"], BOOL_DEFAULT_RATIO); err != nil {
+		return values, 0, err
+	}
+	return values, ratio, nil
+}
+
+type BoolGenerator struct {
+	*CacheGenerator
+
+	values [2]string
+	ratio  float64
+}
+
+func NewBoolGenerator(options *generator.GeneratorOptions, values [2]string, ratio float64) *BoolGenerator {
+	return &BoolGenerator{
+		CacheGenerator: NewCacheGenerator(options, BOOL_GENERATOR_NAME, func() (string, error) {
+			if options.Rand.Float64() < ratio {
+				return values[0], nil
+			}
+			return values[1], nil
+		}),
+		values: values,
+		ratio:  ratio,
+	}
+}
@@ -2,19 +2,37 @@ package generators
 
 import (
 	"fmt"
+	"sync"
 
 	"github.com/welschmorgan/datagen/pkg/generator"
 )
 
 type CacheGenFunc func() (string, error)
 
+// Sampler is implemented by generators able to produce a value without
+// recording it, so composite generators can reuse them under --unique.
+type Sampler interface {
+	Sample() (string, error)
+}
+
+// SampleValue draws a value from g, bypassing uniqueness checks when possible.
+func SampleValue(g generator.Generator) (string, error) {
+	if s, ok := g.(Sampler); ok {
+		return s.Sample()
+	}
+	return g.Next()
+}
+
 type CacheGenerator struct {
 	generator.Generator
 
 	name     string
 	options  *generator.GeneratorOptions
 	gen_func CacheGenFunc
-	seen     []string
+
+	// seenMu guards seen, so that Next stays safe across goroutines under --unique
+	seenMu sync.Mutex
+	seen   map[string]struct{}
 }
 
 func NewCacheGenerator(options *generator.GeneratorOptions, name string, gen_func CacheGenFunc) *CacheGenerator {
@@ -22,6 +40,7 @@ func NewCacheGenerator(options *generator.GeneratorOptions, name string, gen_fun
 		name:     name,
 		options:  options,
 		gen_func: gen_func,
+		seen:     map[string]struct{}{},
 	}
 }
 
@@ -42,9 +61,10 @@ func (g *CacheGenerator) Next() (string, error) {
 	if err != nil {
 		return "", err
 	}
+	// like SQL unique constraints, nulls never collide
 	if g.options.OnlyUniqueValues {
 		numRetries := 1
-		for g.HasSeenValue(next) {
+		for !generator.IsNull(next) && !g.markSeen(next) {
 			if numRetries >= g.options.MaximumUniqueRetries {
 				return "", fmt.Errorf("not enough items, maximum unique retries reached (%d)", g.options.MaximumUniqueRetries)
 			}
@@ -53,16 +73,28 @@ func (g *CacheGenerator) Next() (string, error) {
 				return "", err
 			}
 		}
-		g.seen = append(g.seen, next)
 	}
 	return next, nil
 }
 
+func (g *CacheGenerator) Sample() (string, error) {
+	return g.gen_func()
+}
+
 func (g *CacheGenerator) HasSeenValue(v string) bool {
-	for _, seen := range g.seen {
-		if seen == v {
-			return true
-		}
+	g.seenMu.Lock()
+	defer g.seenMu.Unlock()
+	_, ok := g.seen[v]
+	return ok
+}
+
+// markSeen records v, reporting false if it was already seen.
+func (g *CacheGenerator) markSeen(v string) bool {
+	g.seenMu.Lock()
+	defer g.seenMu.Unlock()
+	if _, ok := g.seen[v]; ok {
+		return false
 	}
-	return false
+	g.seen[v] = struct{}{}
+	return true
 }
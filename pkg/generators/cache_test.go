@@ -0,0 +1,50 @@
+package generators_test
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestCacheGeneratorConcurrentUnique(t *testing.T) {
+	options := generator.NewGeneratorOptions()
+	options.OnlyUniqueValues = true
+	options.MaximumUniqueRetries = 1000
+	// 200 distinct values drawn in turn, so that goroutines keep colliding
+	var n atomic.Int64
+	g := generators.NewCacheGenerator(options, "cycle", func() (string, error) {
+		return fmt.Sprint(n.Add(1) % 200), nil
+	})
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := map[string]int{}
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 25 {
+				v, err := g.Next()
+				if err != nil {
+					t.Errorf("failed to generate unique value, %s", err)
+					return
+				}
+				mu.Lock()
+				seen[v]++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	for v, count := range seen {
+		if count > 1 {
+			t.Errorf("expected unique values but got '%s' %d times", v, count)
+		}
+	}
+	if len(seen) != 200 {
+		t.Errorf("expected 200 distinct values but got %d", len(seen))
+	}
+}
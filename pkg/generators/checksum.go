@@ -0,0 +1,74 @@
+package generators
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strings"
+)
+
+// LuhnCheckDigit returns the digit to append to digits so that the whole
+// number passes the Luhn algorithm.
+func LuhnCheckDigit(digits string) int {
+	sum := 0
+	double := true
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return (10 - sum%10) % 10
+}
+
+// LuhnValid reports whether digits, check digit included, passes the Luhn algorithm.
+func LuhnValid(digits string) bool {
+	if len(digits) < 2 {
+		return false
+	}
+	return LuhnCheckDigit(digits[:len(digits)-1]) == int(digits[len(digits)-1]-'0')
+}
+
+// Mod97 computes the remainder of an arbitrarily long alphanumeric number,
+// letters being worth 10 (A) to 35 (Z) as in ISO 7064.
+func Mod97(s string) (int, error) {
+	rem := 0
+	for _, r := range strings.ToUpper(s) {
+		switch {
+		case r >= '0' && r <= '9':
+			rem = (rem*10 + int(r-'0')) % 97
+		case r >= 'A' && r <= 'Z':
+			rem = (rem*100 + int(r-'A') + 10) % 97
+		default:
+			return 0, fmt.Errorf("invalid character '%c' in '%s'", r, s)
+		}
+	}
+	return rem, nil
+}
+
+// randDigits returns n random decimal digits.
+func randDigits(rng *rand.Rand, n int) string {
+	buf := strings.Builder{}
+	for range n {
+		buf.WriteByte(byte('0' + rng.IntN(10)))
+	}
+	return buf.String()
+}
+
+// splitEvery splits s in groups of n characters, the last one being shorter.
+func splitEvery(s string, n int) []string {
+	groups := []string{}
+	for len(s) > n {
+		groups = append(groups, s[:n])
+		s = s[n:]
+	}
+	return append(groups, s)
+}
+
+// groupBy splits s in space separated groups of n characters.
+func groupBy(s string, n int) string {
+	return strings.Join(splitEvery(s, n), " ")
+}
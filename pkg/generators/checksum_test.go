@@ -0,0 +1,103 @@
+package generators_test
+
+import (
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestLuhn(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	if !generators.LuhnValid("4539578763621486") {
+		t.Errorf("expected '4539578763621486' to be Luhn-valid")
+	}
+	if generators.LuhnValid("4539578763621487") {
+		t.Errorf("expected '4539578763621487' to be Luhn-invalid")
+	}
+	for network, n := range generators.CardNetworks {
+		for range 20 {
+			number, err := generators.RandCardNumber(src, network)
+			if err != nil {
+				t.Fatalf("failed to generate %s card number, %s", network, err)
+			}
+			if len(number) != n.Length || !generators.LuhnValid(number) {
+				t.Errorf("invalid %s card number '%s'", network, number)
+			}
+		}
+	}
+}
+
+func TestIBAN(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	if !generators.IBANValid("FR76 3000 6000 0112 3456 7890 189") {
+		t.Errorf("expected reference IBAN to be valid")
+	}
+	// reference IBANs, then the same with a wrong national key
+	for _, iban := range []string{
+		"BE68539007547034",
+		"ES9121000418450200051332",
+		"FR7630006000011234567890189",
+		"IT60X0542811101000000123456",
+		"PT50000201231234567890154",
+	} {
+		if !generators.IBANValid(iban) || !generators.BBANKeyValid(iban[:2], iban[4:]) {
+			t.Errorf("expected reference IBAN '%s' to be valid", iban)
+		}
+	}
+	for country, bban := range map[string]string{
+		"BE": "539007547035",
+		"ES": "21000418460200051332",
+		"FR": "30006000011234567890188",
+		"IT": "Y0542811101000000123456",
+		"PT": "000201231234567890155",
+	} {
+		if generators.BBANKeyValid(country, bban) {
+			t.Errorf("expected %s BBAN '%s' to have an invalid national key", country, bban)
+		}
+	}
+	for country := range generators.IBANLayouts {
+		for range 20 {
+			iban, err := generators.RandIBAN(src, country)
+			if err != nil {
+				t.Fatalf("failed to generate %s IBAN, %s", country, err)
+			}
+			if !strings.HasPrefix(iban, country) || !generators.IBANValid(iban) || !generators.BBANKeyValid(country, iban[4:]) {
+				t.Errorf("invalid %s IBAN '%s'", country, iban)
+			}
+		}
+	}
+}
+
+func TestNIR(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	if key, _ := generators.NIRKey("1841276451089"); key != "46" {
+		t.Errorf("invalid NIR key, expected '46' but got '%s'", key)
+	}
+	if key, _ := generators.NIRKey("184122A451089"); key != "33" {
+		t.Errorf("invalid corsican NIR key, expected '33' but got '%s'", key)
+	}
+	for range 50 {
+		nir, err := generators.RandNIR(src)
+		if err != nil {
+			t.Fatalf("failed to generate NIR, %s", err)
+		}
+		if key, _ := generators.NIRKey(nir[:13]); len(nir) != 15 || key != nir[13:] {
+			t.Errorf("invalid NIR '%s'", nir)
+		}
+	}
+}
+
+func TestSIRENAndSIRET(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	for range 50 {
+		if siren := generators.RandSIREN(src); len(siren) != 9 || !generators.LuhnValid(siren) {
+			t.Errorf("invalid SIREN '%s'", siren)
+		}
+		siret := generators.RandSIRET(src)
+		if len(siret) != 14 || !generators.LuhnValid(siret) || !generators.LuhnValid(siret[:9]) {
+			t.Errorf("invalid SIRET '%s'", siret)
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package generators
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const DATETIME_GENERATOR_NAME = "datetime"
+
+const (
+	DATETIME_LAYOUT_DATE      = "2006-01-02"
+	DATETIME_LAYOUT_DATETIME  = "2006-01-02 15:04:05"
+	DATETIME_LAYOUT_UNIX      = "unix"
+	DATETIME_LAYOUT_UNIXMILLI = "unixmilli"
+)
+
+var DatetimeOptions = []string{"layout", "tz"}
+
+// DatetimeLayouts maps layout aliases to Go layouts.
+var DatetimeLayouts = map[string]string{
+	"date":     DATETIME_LAYOUT_DATE,
+	"datetime": DATETIME_LAYOUT_DATETIME,
+	"time":     time.TimeOnly,
+	"rfc3339":  time.RFC3339,
+	"rfc1123":  time.RFC1123,
+	"kitchen":  time.Kitchen,
+}
+
+var datetimeBoundLayouts = []string{
+	time.RFC3339,
+	DATETIME_LAYOUT_DATETIME,
+	"2006-01-02T15:04:05",
+	DATETIME_LAYOUT_DATE,
+}
+
+type DatetimeRange struct {
+	min time.Time
+	max time.Time
+
+	layout   string
+	location *time.Location
+}
+
+func (r *DatetimeRange) Bounds() (time.Time, time.Time) {
+	return r.min, r.max
+}
+
+func (r *DatetimeRange) Layout() string {
+	return r.layout
+}
+
+func (r *DatetimeRange) Location() *time.Location {
+	return r.location
+}
+
+func (r *DatetimeRange) Rand(rng *rand.Rand) time.Time {
+	span := r.max.Unix() - r.min.Unix()
+	return time.Unix(r.min.Unix()+rng.Int64N(span+1), 0).In(r.location)
+}
+
+func (r *DatetimeRange) Format(t time.Time) string {
+	switch r.layout {
+	case DATETIME_LAYOUT_UNIX:
+		return strconv.FormatInt(t.Unix(), 10)
+	case DATETIME_LAYOUT_UNIXMILLI:
+		return strconv.FormatInt(t.UnixMilli(), 10)
+	}
+	return t.Format(r.layout)
+}
+
+// ParseDatetimeRange parses 'min..max;layout=...;tz=...' where bounds are
+// dates, timestamps, 'now', 'today' or offsets relative to now such as '-30d'.
+// Ranges are in UTC unless tz names another zone, so that outputs never
+// depend on the host.
+func ParseDatetimeRange(now time.Time, params ...any) (*DatetimeRange, error) {
+	spec, opts, err := ParseSpecOptions(DatetimeOptions, params...)
+	if err != nil {
+		return nil, err
+	}
+	ret := &DatetimeRange{location: time.UTC}
+	if tz, ok := opts["tz"]; ok {
+		if ret.location, err = time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("invalid timezone '%s', %s", tz, err)
+		}
+	}
+	now = now.In(ret.location)
+	parts := strings.Split(spec, "..")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid datetime range, expected 'min..max' but got '%s'", spec)
+	}
+	dateOnly := true
+	bounds := [2]time.Time{}
+	for i, part := range parts {
+		var isDate bool
+		if bounds[i], isDate, err = parseDatetimeBound(strings.TrimSpace(part), now, ret.location); err != nil {
+			return nil, err
+		}
+		dateOnly = dateOnly && isDate
+	}
+	ret.min, ret.max = bounds[0], bounds[1]
+	if ret.min.After(ret.max) {
+		return nil, fmt.Errorf("invalid datetime range '%s', min is after max", spec)
+	}
+	ret.layout = time.RFC3339
+	if dateOnly {
+		ret.layout = DATETIME_LAYOUT_DATE
+	}
+	if layout, ok := opts["layout"]; ok && len(layout) > 0 {
+		ret.layout = layout
+		if alias, ok := DatetimeLayouts[strings.ToLower(layout)]; ok {
+			ret.layout = alias
+		} else if strings.EqualFold(layout, DATETIME_LAYOUT_UNIX) || strings.EqualFold(layout, DATETIME_LAYOUT_UNIXMILLI) {
+			ret.layout = strings.ToLower(layout)
+		}
+	}
+	return ret, nil
+}
+
+// parseDatetimeBound returns the bound's time and whether it only holds a date.
+func parseDatetimeBound(s string, now time.Time, loc *time.Location) (time.Time, bool, error) {
+	switch strings.ToLower(s) {
+	case "now":
+		return now, false, nil
+	case "today":
+		y, m, d := now.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, loc), true, nil
+	}
+	if len(s) > 1 && (s[0] == '-' || s[0] == '+') {
+		return parseDatetimeOffset(s, now)
+	}
+	for _, layout := range datetimeBoundLayouts {
+		if t, err := time.ParseInLocation(layout, s, loc); err == nil {
+			return t, layout == DATETIME_LAYOUT_DATE, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("invalid datetime bound '%s', expected a date, a timestamp, 'now', 'today' or an offset like '-30d'", s)
+}
+
+// parseDatetimeOffset handles offsets relative to now, using the units
+// s(econds), m(inutes), h(ours), d(ays), w(eeks), M(onths) and y(ears).
+func parseDatetimeOffset(s string, now time.Time) (time.Time, bool, error) {
+	unit := s[len(s)-1]
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid datetime offset '%s', %s", s, err)
+	}
+	switch unit {
+	case 's':
+		return now.Add(time.Duration(n) * time.Second), false, nil
+	case 'm':
+		return now.Add(time.Duration(n) * time.Minute), false, nil
+	case 'h':
+		return now.Add(time.Duration(n) * time.Hour), false, nil
+	case 'd':
+		return now.AddDate(0, 0, n), false, nil
+	case 'w':
+		return now.AddDate(0, 0, 7*n), false, nil
+	case 'M':
+		return now.AddDate(0, n, 0), false, nil
+	case 'y':
+		return now.AddDate(n, 0, 0), false, nil
+	}
+	return time.Time{}, false, fmt.Errorf("invalid datetime offset unit '%c' in '%s', expected one of s, m, h, d, w, M, y", unit, s)
+}
+
+type DatetimeGenerator struct {
+	*CacheGenerator
+
+	range_ *DatetimeRange
+}
+
+func NewDatetimeGenerator(options *generator.GeneratorOptions, range_ *DatetimeRange) *DatetimeGenerator {
+	return &DatetimeGenerator{
+		CacheGenerator: NewCacheGenerator(options, DATETIME_GENERATOR_NAME, func() (string, error) {
+			return range_.Format(range_.Rand(options.Rand)), nil
+		}),
+		range_: range_,
+	}
+}
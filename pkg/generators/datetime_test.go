@@ -0,0 +1,61 @@
+package generators_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestParseDatetimeRange(t *testing.T) {
+	now := time.Date(2024, 6, 15, 12, 0, 0, 0, time.UTC)
+	rng, err := generators.ParseDatetimeRange(now, "datetime", "-30d..now;tz=UTC")
+	if err != nil {
+		t.Fatalf("failed to parse datetime range, %s", err)
+	}
+	min, max := rng.Bounds()
+	if !min.Equal(now.AddDate(0, 0, -30)) || !max.Equal(now) {
+		t.Errorf("invalid bounds, got '%s..%s'", min, max)
+	}
+	if rng.Layout() != time.RFC3339 {
+		t.Errorf("invalid default layout, expected '%s' but got '%s'", time.RFC3339, rng.Layout())
+	}
+}
+
+func TestParseDatetimeRangeWithLayout(t *testing.T) {
+	// the ':' separator of the layout is split by the resource loader
+	rng, err := generators.ParseDatetimeRange(time.Now(), "datetime", "1950-01-01..2005-12-31;layout=15", "04;tz=Europe/Paris")
+	if err != nil {
+		t.Fatalf("failed to parse datetime range, %s", err)
+	}
+	if rng.Layout() != "15:04" {
+		t.Errorf("invalid layout, expected '15:04' but got '%s'", rng.Layout())
+	}
+	if rng.Location().String() != "Europe/Paris" {
+		t.Errorf("invalid location, expected 'Europe/Paris' but got '%s'", rng.Location())
+	}
+	dateRng, err := generators.ParseDatetimeRange(time.Now(), "datetime", "1950-01-01..2005-12-31;layout=unix")
+	if err != nil {
+		t.Fatalf("failed to parse datetime range, %s", err)
+	}
+	if v := dateRng.Format(time.Unix(42, 0)); v != "42" {
+		t.Errorf("invalid unix output, expected '42' but got '%s'", v)
+	}
+}
+
+func TestParseDatetimeRangeDefaultsToUTC(t *testing.T) {
+	local := time.Local
+	defer func() { time.Local = local }()
+	time.Local = time.FixedZone("UTC+5", 5*60*60)
+	now := time.Date(2024, 6, 15, 22, 0, 0, 0, time.UTC)
+	rng, err := generators.ParseDatetimeRange(now, "datetime", "today..today")
+	if err != nil {
+		t.Fatalf("failed to parse datetime range, %s", err)
+	}
+	if rng.Location() != time.UTC {
+		t.Errorf("invalid location, expected 'UTC' but got '%s'", rng.Location())
+	}
+	if min, _ := rng.Bounds(); rng.Format(min) != "2024-06-15" {
+		t.Errorf("expected 'today' to be '2024-06-15' in UTC but got '%s'", rng.Format(min))
+	}
+}
@@ -0,0 +1,309 @@
+package generators
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DISTRIBUTION_MAX_RETRIES bounds the resampling of values falling outside
+// of the range, before falling back to a uniform value.
+const DISTRIBUTION_MAX_RETRIES = 1000
+
+// DISTRIBUTION_MIN_MASS is the share of a distribution that must lie within
+// its range, making the uniform fallback of resampling practically unreachable.
+const DISTRIBUTION_MIN_MASS = 0.05
+
+// Distribution draws values within [min, max].
+type Distribution interface {
+	Sample(rng *rand.Rand, min, max float64) float64
+	// Mass returns the probability of the untruncated distribution to fall
+	// within [min, max].
+	Mass(min, max float64) float64
+	String() string
+}
+
+// CheckDistributionMass fails when too little of d lies within [min, max],
+// such as 'normal(5000,10)' on '0..100'.
+func CheckDistributionMass(d Distribution, min, max float64) error {
+	if mass := d.Mass(min, max); mass < DISTRIBUTION_MIN_MASS {
+		return fmt.Errorf("invalid distribution '%s', only %.2g%% of it lies within %g..%g", d, mass*100, min, max)
+	}
+	return nil
+}
+
+// truncated resamples f until it falls within [min, max]. Values are never
+// clipped, which would pile the tails up on the bounds.
+func truncated(rng *rand.Rand, d Distribution, min, max float64, f func() float64) float64 {
+	for range DISTRIBUTION_MAX_RETRIES {
+		if v := f(); v >= min && v <= max {
+			return v
+		}
+	}
+	slog.Warn(fmt.Sprintf("Distribution '%s' keeps falling outside of %g..%g, drawing a uniform value", d, min, max))
+	return min + rng.Float64()*(max-min)
+}
+
+// normalCDF returns the probability of a standard normal value to be below x.
+func normalCDF(x float64) float64 {
+	return 0.5 * (1 + math.Erf(x/math.Sqrt2))
+}
+
+type NormalDistribution struct {
+	Mean   float64
+	StdDev float64
+}
+
+func (d *NormalDistribution) Sample(rng *rand.Rand, min, max float64) float64 {
+	return truncated(rng, d, min, max, func() float64 {
+		return rng.NormFloat64()*d.StdDev + d.Mean
+	})
+}
+
+func (d *NormalDistribution) Mass(min, max float64) float64 {
+	return normalCDF((max-d.Mean)/d.StdDev) - normalCDF((min-d.Mean)/d.StdDev)
+}
+
+func (d *NormalDistribution) String() string {
+	return fmt.Sprintf("normal(%g,%g)", d.Mean, d.StdDev)
+}
+
+// LogNormalDistribution is parametrized by the mean and standard deviation of
+// the underlying normal distribution.
+type LogNormalDistribution struct {
+	Mu    float64
+	Sigma float64
+}
+
+func (d *LogNormalDistribution) Sample(rng *rand.Rand, min, max float64) float64 {
+	return truncated(rng, d, min, max, func() float64 {
+		return math.Exp(rng.NormFloat64()*d.Sigma + d.Mu)
+	})
+}
+
+func (d *LogNormalDistribution) Mass(min, max float64) float64 {
+	if max <= 0 {
+		return 0
+	}
+	lo := 0.0
+	if min > 0 {
+		lo = normalCDF((math.Log(min) - d.Mu) / d.Sigma)
+	}
+	return normalCDF((math.Log(max)-d.Mu)/d.Sigma) - lo
+}
+
+func (d *LogNormalDistribution) String() string {
+	return fmt.Sprintf("lognormal(%g,%g)", d.Mu, d.Sigma)
+}
+
+// ExponentialDistribution decays from the lower bound of the range.
+type ExponentialDistribution struct {
+	Rate float64
+}
+
+func (d *ExponentialDistribution) Sample(rng *rand.Rand, min, max float64) float64 {
+	return truncated(rng, d, min, max, func() float64 {
+		return min + rng.ExpFloat64()/d.Rate
+	})
+}
+
+func (d *ExponentialDistribution) Mass(min, max float64) float64 {
+	return 1 - math.Exp(-d.Rate*(max-min))
+}
+
+func (d *ExponentialDistribution) String() string {
+	return fmt.Sprintf("exponential(%g)", d.Rate)
+}
+
+// PoissonDistribution counts events from the lower bound of the range.
+type PoissonDistribution struct {
+	Lambda float64
+}
+
+func (d *PoissonDistribution) Sample(rng *rand.Rand, min, max float64) float64 {
+	return truncated(rng, d, min, max, func() float64 {
+		if d.Lambda > 500 {
+			// normal approximation, exp(-lambda) underflows for big lambdas
+			return min + math.Round(rng.NormFloat64()*math.Sqrt(d.Lambda)+d.Lambda)
+		}
+		l := math.Exp(-d.Lambda)
+		k := 0.0
+		for p := rng.Float64(); p > l; p *= rng.Float64() {
+			k++
+		}
+		return min + k
+	})
+}
+
+func (d *PoissonDistribution) Mass(min, max float64) float64 {
+	n := math.Floor(max - min)
+	if d.Lambda > 500 {
+		return normalCDF((n + 0.5 - d.Lambda) / math.Sqrt(d.Lambda))
+	}
+	// counts this far above lambda are negligible
+	if n > d.Lambda+20*math.Sqrt(d.Lambda)+20 {
+		return 1
+	}
+	p := math.Exp(-d.Lambda)
+	mass := 0.0
+	for k := 0.0; k <= n; k++ {
+		mass += p
+		p *= d.Lambda / (k + 1)
+	}
+	return mass
+}
+
+func (d *PoissonDistribution) String() string {
+	return fmt.Sprintf("poisson(%g)", d.Lambda)
+}
+
+// ZipfDistribution favors the lower bound of the range, with s > 1 and v >= 1.
+type ZipfDistribution struct {
+	S float64
+	V float64
+
+	mu   sync.Mutex
+	rng  *rand.Rand
+	zipf *rand.Zipf
+	imax uint64
+}
+
+func (d *ZipfDistribution) Sample(rng *rand.Rand, min, max float64) float64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	imax := uint64(max - min)
+	// rand.Zipf is bound to its source, so it is rebuilt when the source changes
+	if d.zipf == nil || d.imax != imax || d.rng != rng {
+		d.zipf = rand.NewZipf(rng, d.S, d.V, imax)
+		d.rng = rng
+		d.imax = imax
+	}
+	return min + float64(d.zipf.Uint64())
+}
+
+// Mass is always 1, values being drawn within the range only.
+func (d *ZipfDistribution) Mass(min, max float64) float64 {
+	return 1
+}
+
+func (d *ZipfDistribution) String() string {
+	return fmt.Sprintf("zipf(%g,%g)", d.S, d.V)
+}
+
+// HistogramDistribution splits the range into equally sized buckets, each
+// bucket being picked according to its weight.
+type HistogramDistribution struct {
+	Weights []float64
+	total   float64
+}
+
+func (d *HistogramDistribution) Sample(rng *rand.Rand, min, max float64) float64 {
+	n := rng.Float64() * d.total
+	bucket := len(d.Weights) - 1
+	for i, w := range d.Weights {
+		if n < w {
+			bucket = i
+			break
+		}
+		n -= w
+	}
+	size := (max - min) / float64(len(d.Weights))
+	return min + size*(float64(bucket)+rng.Float64())
+}
+
+// Mass is always 1, buckets splitting the range.
+func (d *HistogramDistribution) Mass(min, max float64) float64 {
+	return 1
+}
+
+func (d *HistogramDistribution) String() string {
+	weights := []string{}
+	for _, w := range d.Weights {
+		weights = append(weights, strconv.FormatFloat(w, 'g', -1, 64))
+	}
+	return fmt.Sprintf("histogram(%s)", strings.Join(weights, ","))
+}
+
+// ParseDistribution parses declarations such as 'normal(50,10)'. Distributions
+// are truncated to their range: values falling outside of it are drawn again
+// rather than clipped to the bounds, which would pile the tails up on them.
+func ParseDistribution(s string) (Distribution, error) {
+	s = strings.TrimSpace(s)
+	name, rest, ok := strings.Cut(s, "(")
+	if !ok || !strings.HasSuffix(rest, ")") {
+		return nil, fmt.Errorf("invalid distribution '%s', expected 'name(arg,...)'", s)
+	}
+	args := []float64{}
+	for _, arg := range strings.Split(strings.TrimSuffix(rest, ")"), ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(arg), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid distribution argument '%s' in '%s', %s", arg, s, err)
+		}
+		args = append(args, v)
+	}
+	expect := func(n int, names string) error {
+		if len(args) != n {
+			return fmt.Errorf("invalid distribution '%s', expected %d arguments (%s) but got %d", s, n, names, len(args))
+		}
+		return nil
+	}
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "normal":
+		if err := expect(2, "mean, stddev"); err != nil {
+			return nil, err
+		}
+		if args[1] <= 0 {
+			return nil, fmt.Errorf("invalid distribution '%s', stddev must be positive", s)
+		}
+		return &NormalDistribution{Mean: args[0], StdDev: args[1]}, nil
+	case "lognormal":
+		if err := expect(2, "mu, sigma"); err != nil {
+			return nil, err
+		}
+		if args[1] <= 0 {
+			return nil, fmt.Errorf("invalid distribution '%s', sigma must be positive", s)
+		}
+		return &LogNormalDistribution{Mu: args[0], Sigma: args[1]}, nil
+	case "exponential":
+		if err := expect(1, "rate"); err != nil {
+			return nil, err
+		}
+		if args[0] <= 0 {
+			return nil, fmt.Errorf("invalid distribution '%s', rate must be positive", s)
+		}
+		return &ExponentialDistribution{Rate: args[0]}, nil
+	case "poisson":
+		if err := expect(1, "lambda"); err != nil {
+			return nil, err
+		}
+		if args[0] <= 0 {
+			return nil, fmt.Errorf("invalid distribution '%s', lambda must be positive", s)
+		}
+		return &PoissonDistribution{Lambda: args[0]}, nil
+	case "zipf":
+		if err := expect(2, "s, v"); err != nil {
+			return nil, err
+		}
+		if args[0] <= 1 || args[1] < 1 {
+			return nil, fmt.Errorf("invalid distribution '%s', expected s > 1 and v >= 1", s)
+		}
+		return &ZipfDistribution{S: args[0], V: args[1]}, nil
+	case "histogram":
+		ret := &HistogramDistribution{Weights: args}
+		for _, w := range args {
+			if w < 0 {
+				return nil, fmt.Errorf("invalid distribution '%s', weights must be positive", s)
+			}
+			ret.total += w
+		}
+		if ret.total <= 0 {
+			return nil, fmt.Errorf("invalid distribution '%s', total weight must be positive", s)
+		}
+		return ret, nil
+	}
+	return nil, fmt.Errorf("unknown distribution '%s', expected one of normal, lognormal, exponential, poisson, zipf or histogram", name)
+}
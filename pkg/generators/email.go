@@ -0,0 +1,176 @@
+package generators
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/utils"
+)
+
+const EMAIL_GENERATOR_NAME = "email"
+
+const EMAIL_PROVIDER_TABLE = "misc_prop"
+const EMAIL_PROVIDER_TYPE = "email-provider"
+const EMAIL_PROVIDER_PLACEHOLDER = "provider"
+
+var EmailPlaceholder = regexp.MustCompile(`\{([^{}]+)\}`)
+
+var EmailOptions = []string{"locale"}
+
+type EmailSettings struct {
+	Templates []string
+	// Locale restricts providers to those of a locale, all being used when empty
+	Locale string
+}
+
+type EmailGenerator struct {
+	*CacheGenerator
+
+	db        *sql.DB
+	settings  *EmailSettings
+	resGetter func(name string) generator.Generator
+
+	once      sync.Once
+	loadErr   error
+	providers []string
+}
+
+// ParseEmail parses 'template,...;locale=fr-FR'.
+func ParseEmail(params ...any) (*EmailSettings, error) {
+	spec, opts, err := ParseSpecOptions(EmailOptions, params...)
+	if err != nil {
+		return nil, err
+	}
+	ret := &EmailSettings{Locale: opts["locale"]}
+	for _, tpl := range strings.Split(spec, ",") {
+		tpl = strings.TrimSpace(tpl)
+		if len(tpl) == 0 {
+			continue
+		}
+		if strings.Count(tpl, "@") != 1 {
+			return nil, fmt.Errorf("invalid email template '%s', expected exactly one '@'", tpl)
+		}
+		ret.Templates = append(ret.Templates, tpl)
+	}
+	if len(ret.Templates) == 0 {
+		return nil, fmt.Errorf("invalid email template, no variant found in %v", params)
+	}
+	return ret, nil
+}
+
+func NewEmailGenerator(db *sql.DB, options *generator.GeneratorOptions, settings *EmailSettings, resGetter func(name string) generator.Generator) *EmailGenerator {
+	ret := &EmailGenerator{
+		db:        db,
+		settings:  settings,
+		resGetter: resGetter,
+	}
+	ret.CacheGenerator = NewCacheGenerator(options, EMAIL_GENERATOR_NAME, ret.next)
+	return ret
+}
+
+func (g *EmailGenerator) Validate() error {
+	for _, tpl := range g.settings.Templates {
+		for _, match := range EmailPlaceholder.FindAllStringSubmatch(tpl, -1) {
+			name := strings.TrimSpace(match[1])
+			if name != EMAIL_PROVIDER_PLACEHOLDER && g.resGetter(name) == nil {
+				return fmt.Errorf("unknown resource '%s' in email template '%s'", name, tpl)
+			}
+		}
+	}
+	return CheckReferenceCycles(g.References(), g.resGetter)
+}
+
+func (g *EmailGenerator) References() []string {
+	ret := []string{}
+	for _, tpl := range g.settings.Templates {
+		for _, match := range EmailPlaceholder.FindAllStringSubmatch(tpl, -1) {
+			if name := strings.TrimSpace(match[1]); name != EMAIL_PROVIDER_PLACEHOLDER {
+				ret = append(ret, name)
+			}
+		}
+	}
+	return ret
+}
+
+func (g *EmailGenerator) next() (string, error) {
+	tpl := g.settings.Templates[g.options.Rand.IntN(len(g.settings.Templates))]
+	var err error
+	null := false
+	ret := EmailPlaceholder.ReplaceAllStringFunc(tpl, func(match string) string {
+		if err != nil || null {
+			return ""
+		}
+		name := strings.TrimSpace(match[1 : len(match)-1])
+		var value string
+		if name == EMAIL_PROVIDER_PLACEHOLDER {
+			value, err = g.provider()
+			return value
+		}
+		gen := g.resGetter(name)
+		if gen == nil {
+			err = fmt.Errorf("unknown resource '%s' in email template '%s'", name, tpl)
+			return ""
+		}
+		if value, err = SampleValue(gen); err != nil {
+			return ""
+		}
+		null = generator.IsNull(value)
+		return NormalizeEmailPart(value)
+	})
+	if err != nil {
+		return "", err
+	}
+	// a missing part would yield an invalid address such as '.dupont@gmail.com'
+	if null {
+		return generator.NULL_VALUE, nil
+	}
+	return ret, nil
+}
+
+func (g *EmailGenerator) loadProviders() error {
+	rawQuery := fmt.Sprintf("SELECT p.value FROM %s p WHERE p.type = ? ORDER BY p.id", EMAIL_PROVIDER_TABLE)
+	params := []any{EMAIL_PROVIDER_TYPE}
+	if len(g.settings.Locale) > 0 {
+		rawQuery = fmt.Sprintf("SELECT p.value FROM %s p JOIN locale l ON l.id = p.locale_id WHERE p.type = ? AND l.name = ? ORDER BY p.id", EMAIL_PROVIDER_TABLE)
+		params = append(params, g.settings.Locale)
+	}
+	rows, err := g.db.Query(rawQuery, params...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return fmt.Errorf("failed to scan rows: %s", err)
+		}
+		g.providers = append(g.providers, strings.ToLower(value))
+	}
+	if len(g.providers) == 0 {
+		return fmt.Errorf("invalid email generator, no provider found: '%s' (params=%v)", rawQuery, params)
+	}
+	return nil
+}
+
+func (g *EmailGenerator) provider() (string, error) {
+	g.once.Do(func() {
+		g.loadErr = g.loadProviders()
+	})
+	if g.loadErr != nil {
+		return "", g.loadErr
+	}
+	return g.providers[g.options.Rand.IntN(len(g.providers))], nil
+}
+
+// NormalizeEmailPart lowercases and ASCII-folds a value so it can be used in
+// the local part of an address, dropping anything not allowed there.
+func NormalizeEmailPart(s string) string {
+	s = strings.ToLower(utils.ASCIIFold(s))
+	return utils.KeepRunes(s, func(r rune) bool {
+		return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '-' || r == '_'
+	})
+}
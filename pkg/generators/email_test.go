@@ -0,0 +1,106 @@
+package generators_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestNormalizeEmailPart(t *testing.T) {
+	cases := map[string]string{
+		"Hélène":         "helene",
+		"DE LA FONTAINE": "delafontaine",
+		"Jean-Pierre":    "jean-pierre",
+	}
+	for input, expected := range cases {
+		if got := generators.NormalizeEmailPart(input); got != expected {
+			t.Errorf("invalid email part for '%s', expected '%s' but got '%s'", input, expected, got)
+		}
+	}
+}
+
+func TestEmailGeneratorNulls(t *testing.T) {
+	options := generator.NewGeneratorOptions()
+	var resources map[string]generator.Generator
+	resGetter := func(name string) generator.Generator {
+		return resources[name]
+	}
+	resources = map[string]generator.Generator{
+		"first":    generators.NewSequenceGenerator(options, mustParseSequence(t, "Jean%d")),
+		"last":     generators.NewNullableGenerator(options, "first", 1, resGetter),
+		"nickName": generators.NewNullableGenerator(options, "first", 0, resGetter),
+	}
+	settings, err := generators.ParseEmail("email", "{last}.{first}@example.com")
+	if err != nil {
+		t.Fatalf("failed to parse email templates, %s", err)
+	}
+	if v, err := generators.NewEmailGenerator(nil, options, settings, resGetter).Next(); err != nil || !generator.IsNull(v) {
+		t.Errorf("expected a null address when a part is null but got '%s' (%v)", v, err)
+	}
+	settings, _ = generators.ParseEmail("email", "{nickName}.{first}@example.com")
+	if v, err := generators.NewEmailGenerator(nil, options, settings, resGetter).Next(); err != nil || v != "jean1.jean2@example.com" {
+		t.Errorf("expected 'jean1.jean2@example.com' but got '%s' (%v)", v, err)
+	}
+}
+
+func TestEmailGeneratorValidate(t *testing.T) {
+	options := generator.NewGeneratorOptions()
+	resources := map[string]generator.Generator{
+		"first": generators.NewSequenceGenerator(options, mustParseSequence(t, "Jean%d")),
+	}
+	resGetter := func(name string) generator.Generator {
+		return resources[name]
+	}
+	settings, _ := generators.ParseEmail("email", "{first}@{provider}, {first}.{ last }@example.com")
+	if err := generators.NewEmailGenerator(nil, options, settings, resGetter).Validate(); err == nil {
+		t.Errorf("expected an error for the unknown resource 'last'")
+	}
+	settings, _ = generators.ParseEmail("email", "{first}@{provider}")
+	if err := generators.NewEmailGenerator(nil, options, settings, resGetter).Validate(); err != nil {
+		t.Errorf("expected a valid email generator but got %s", err)
+	}
+}
+
+func TestEmailProvidersOfLocale(t *testing.T) {
+	db := seededDB(t)
+	options := generator.NewGeneratorOptions()
+	resources := map[string]generator.Generator{
+		"first": generators.NewSequenceGenerator(options, mustParseSequence(t, "jean%d")),
+	}
+	resGetter := func(name string) generator.Generator {
+		return resources[name]
+	}
+	french := []string{"gmail.com", "orange.fr", "free.fr", "laposte.net", "sfr.fr", "hotmail.fr"}
+	settings, err := generators.ParseEmail("email", "{first}@{provider};locale=fr-FR")
+	if err != nil {
+		t.Fatalf("failed to parse email settings, %s", err)
+	}
+	row, err := generators.AllocateGeneratorRandomDB(db)(options, "random_row", "misc_prop", "type=email-provider;locale=fr-FR")
+	if err != nil {
+		t.Fatalf("failed to allocate random_row, %s", err)
+	}
+	g := generators.NewEmailGenerator(db, options, settings, resGetter)
+	for range 200 {
+		email, err := g.Next()
+		if err != nil {
+			t.Fatalf("failed to generate email, %s", err)
+		}
+		if _, provider, _ := strings.Cut(email, "@"); !slices.Contains(french, provider) {
+			t.Fatalf("expected a fr-FR provider but got '%s'", email)
+		}
+		if provider, _ := row.Next(); !slices.Contains(french, provider) {
+			t.Fatalf("expected a fr-FR provider but got '%s'", provider)
+		}
+	}
+	for _, tpl := range []string{"{first}@{provider};region=fr", "{first}"} {
+		if _, err := generators.ParseEmail("email", tpl); err == nil {
+			t.Errorf("expected an error for email template '%s'", tpl)
+		}
+	}
+	if _, err := generators.AllocateGeneratorRandomDB(db)(options, "random_row", "misc_prop", "type=email-provider;region=fr"); err == nil {
+		t.Errorf("expected an error for an unknown random_row option")
+	}
+}
@@ -0,0 +1,181 @@
+package generators
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/utils"
+)
+
+const FILTER_GENERATOR_NAME = "filter"
+
+// Filter transforms a generated value.
+type Filter func(string) string
+
+// FilterFactory builds a filter from the arguments given between parentheses.
+type FilterFactory func(args ...string) (Filter, error)
+
+// asciiReplacer handles letters that do not decompose into a base letter and
+// a diacritic.
+var asciiReplacer = strings.NewReplacer(
+	"æ", "ae", "Æ", "AE", "œ", "oe", "Œ", "OE", "ß", "ss",
+	"ø", "o", "Ø", "O", "ł", "l", "Ł", "L", "đ", "d", "Đ", "D",
+)
+
+var slugSeparators = regexp.MustCompile(`[^a-z0-9]+`)
+
+func noArgs(name string, f Filter) FilterFactory {
+	return func(args ...string) (Filter, error) {
+		if len(args) > 0 {
+			return nil, fmt.Errorf("filter '%s' takes no argument", name)
+		}
+		return f, nil
+	}
+}
+
+// caser wraps a case mapping, building a new cases.Caser for each value as
+// they are stateful and generators run concurrently.
+func caser(mapping func(language.Tag, ...cases.Option) cases.Caser) Filter {
+	return func(s string) string {
+		return mapping(language.Und).String(s)
+	}
+}
+
+// ASCII strips diacritics and drops what remains outside of ASCII.
+func ASCII(s string) string {
+	return utils.KeepRunes(utils.ASCIIFold(asciiReplacer.Replace(s)), func(r rune) bool {
+		return r < 0x80
+	})
+}
+
+// Slug lower cases s to ASCII, joining words with hyphens.
+func Slug(s string) string {
+	return strings.Trim(slugSeparators.ReplaceAllString(strings.ToLower(ASCII(s)), "-"), "-")
+}
+
+var Filters = map[string]FilterFactory{
+	"lower": noArgs("lower", caser(cases.Lower)),
+	"upper": noArgs("upper", caser(cases.Upper)),
+	"title": noArgs("title", caser(cases.Title)),
+	"ascii": noArgs("ascii", ASCII),
+	"slug":  noArgs("slug", Slug),
+	"trim":  noArgs("trim", strings.TrimSpace),
+	"truncate": func(args ...string) (Filter, error) {
+		if len(args) != 1 {
+			return nil, fmt.Errorf("filter 'truncate' takes exactly one argument")
+		}
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid truncate length '%s', expected a positive number", args[0])
+		}
+		return func(s string) string {
+			if runes := []rune(s); len(runes) > n {
+				return string(runes[:n])
+			}
+			return s
+		}, nil
+	},
+}
+
+// ParseFilter parses 'name' or 'name(arg1,arg2)'.
+func ParseFilter(s string) (Filter, error) {
+	s = strings.TrimSpace(s)
+	name, args := s, []string{}
+	if pos := strings.Index(s, "("); pos != -1 {
+		if !strings.HasSuffix(s, ")") {
+			return nil, fmt.Errorf("invalid filter '%s', missing ')'", s)
+		}
+		name = strings.TrimSpace(s[:pos])
+		for _, arg := range strings.Split(s[pos+1:len(s)-1], ",") {
+			args = append(args, strings.TrimSpace(arg))
+		}
+	}
+	factory, ok := Filters[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("unknown filter '%s'", name)
+	}
+	return factory(args...)
+}
+
+// ParseFilterChain splits 'person.lastName|title|ascii' into the resource
+// name and its filters.
+func ParseFilterChain(s string) (resource string, filters []Filter, err error) {
+	parts := strings.Split(s, "|")
+	resource = strings.TrimSpace(parts[0])
+	if len(resource) == 0 {
+		return "", nil, fmt.Errorf("invalid filter chain '%s', expected a resource name", s)
+	}
+	for _, part := range parts[1:] {
+		f, err := ParseFilter(part)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid filter chain '%s', %s", s, err)
+		}
+		filters = append(filters, f)
+	}
+	return resource, filters, nil
+}
+
+// ApplyFilters runs filters in order, null values being left untouched.
+func ApplyFilters(value string, filters []Filter) string {
+	if generator.IsNull(value) {
+		return value
+	}
+	for _, f := range filters {
+		value = f(value)
+	}
+	return value
+}
+
+type FilterGenerator struct {
+	*CacheGenerator
+
+	resource  string
+	filters   []Filter
+	resGetter func(name string) generator.Generator
+}
+
+// ParseFilterArgs parses 'resource|filter|filter(arg)'.
+func ParseFilterArgs(params ...any) (resource string, filters []Filter, err error) {
+	spec, _, err := ParseSpecOptions(nil, params...)
+	if err != nil {
+		return "", nil, err
+	}
+	return ParseFilterChain(spec)
+}
+
+func NewFilterGenerator(options *generator.GeneratorOptions, resource string, filters []Filter, resGetter func(name string) generator.Generator) *FilterGenerator {
+	ret := &FilterGenerator{
+		resource:  resource,
+		filters:   filters,
+		resGetter: resGetter,
+	}
+	ret.CacheGenerator = NewCacheGenerator(options, FILTER_GENERATOR_NAME, func() (string, error) {
+		gen := ret.resGetter(ret.resource)
+		if gen == nil {
+			return "", fmt.Errorf("unknown filtered resource '%s'", ret.resource)
+		}
+		value, err := SampleValue(gen)
+		if err != nil {
+			return "", err
+		}
+		return ApplyFilters(value, ret.filters), nil
+	})
+	return ret
+}
+
+func (g *FilterGenerator) Validate() error {
+	if g.resGetter(g.resource) == nil {
+		return fmt.Errorf("unknown filtered resource '%s'", g.resource)
+	}
+	return CheckReferenceCycles(g.References(), g.resGetter)
+}
+
+func (g *FilterGenerator) References() []string {
+	return []string{g.resource}
+}
@@ -0,0 +1,37 @@
+package generators_test
+
+import (
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestParseFilterChain(t *testing.T) {
+	for _, tc := range []struct{ chain, input, expected string }{
+		{"name|title|ascii", "JEAN-ÉMILE DE LA FORÊT", "Jean-Emile De La Foret"},
+		{"name|lower|slug|truncate(12)", "  Œdipe & Hélène Müller ", "oedipe-helen"},
+		{"name|upper", "ßéa", "SSÉA"},
+		{"name | trim | ascii | truncate(4)", " Łukasz ", "Luka"},
+	} {
+		resource, filters, err := generators.ParseFilterChain(tc.chain)
+		if err != nil {
+			t.Fatalf("failed to parse filter chain '%s', %s", tc.chain, err)
+		}
+		if resource != "name" {
+			t.Errorf("invalid resource '%s' for chain '%s'", resource, tc.chain)
+		}
+		if got := generators.ApplyFilters(tc.input, filters); got != tc.expected {
+			t.Errorf("invalid result for chain '%s', expected '%s' but got '%s'", tc.chain, tc.expected, got)
+		}
+	}
+	for _, chain := range []string{"|lower", "name|unknown", "name|truncate", "name|truncate(x)", "name|lower(1)", "name|truncate(3"} {
+		if _, _, err := generators.ParseFilterChain(chain); err == nil {
+			t.Errorf("expected an error for filter chain '%s'", chain)
+		}
+	}
+	_, filters, _ := generators.ParseFilterChain("name|upper")
+	if got := generators.ApplyFilters(generator.NULL_VALUE, filters); !generator.IsNull(got) {
+		t.Errorf("expected null values to be left untouched, got '%s'", got)
+	}
+}
@@ -0,0 +1,359 @@
+package generators
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const IBAN_GENERATOR_NAME = "iban"
+const CREDIT_CARD_GENERATOR_NAME = "credit_card"
+
+var IBANOptions = []string{"format"}
+var CreditCardOptions = []string{"format"}
+
+// IBANLayouts describes the BBAN of each country as in the SWIFT registry:
+// 'n' digits, 'a' upper case letters, 'c' alphanumeric characters.
+var IBANLayouts = map[string]string{
+	"BE": "3n7n2n",
+	"CH": "5n12c",
+	"DE": "8n10n",
+	"ES": "4n4n1n1n10n",
+	"FR": "5n5n11c2n",
+	"GB": "4a6n8n",
+	"IT": "1a5n5n12c",
+	"LU": "3n13c",
+	"NL": "4a10n",
+	"PT": "4n4n11n2n",
+}
+
+type CardNetwork struct {
+	Prefixes [][2]int
+	Length   int
+}
+
+// CardNetworks lists issuer identification ranges and lengths per network.
+var CardNetworks = map[string]CardNetwork{
+	"visa":       {Prefixes: [][2]int{{4, 4}}, Length: 16},
+	"mastercard": {Prefixes: [][2]int{{51, 55}, {2221, 2720}}, Length: 16},
+	"amex":       {Prefixes: [][2]int{{34, 34}, {37, 37}}, Length: 15},
+	"discover":   {Prefixes: [][2]int{{6011, 6011}, {644, 649}, {65, 65}}, Length: 16},
+	"jcb":        {Prefixes: [][2]int{{3528, 3589}}, Length: 16},
+	"diners":     {Prefixes: [][2]int{{36, 36}, {300, 305}}, Length: 14},
+}
+
+const alnumUpper = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// randBBAN fills a BBAN layout such as '5n5n11c2n'.
+func randBBAN(rng *rand.Rand, layout string) (string, error) {
+	buf := strings.Builder{}
+	count := 0
+	for _, r := range layout {
+		if r >= '0' && r <= '9' {
+			count = count*10 + int(r-'0')
+			continue
+		}
+		var chars string
+		switch r {
+		case 'n':
+			chars = PatternClasses["digit"]
+		case 'a':
+			chars = PatternClasses["upper"]
+		case 'c':
+			chars = alnumUpper
+		default:
+			return "", fmt.Errorf("invalid BBAN layout '%s', unknown character type '%c'", layout, r)
+		}
+		for range count {
+			buf.WriteByte(chars[rng.IntN(len(chars))])
+		}
+		count = 0
+	}
+	return buf.String(), nil
+}
+
+// frenchRIBKey computes the 'clé RIB' of a french bank, branch and account,
+// letters of the account being replaced by digits.
+func frenchRIBKey(bank, branch, account string) (string, error) {
+	letters := "123456789123456789234567890"
+	account = strings.Map(func(r rune) rune {
+		if r >= 'A' && r <= 'Z' {
+			return rune(letters[r-'A'])
+		}
+		return r
+	}, account)
+	b, err := strconv.ParseInt(bank, 10, 64)
+	if err != nil {
+		return "", err
+	}
+	g, err := strconv.ParseInt(branch, 10, 64)
+	if err != nil {
+		return "", err
+	}
+	a, err := strconv.ParseInt(account, 10, 64)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%02d", 97-(89*b+15*g+3*a)%97), nil
+}
+
+// belgianKey computes the mod 97 key of a belgian bank and account, 97
+// standing for a null remainder.
+func belgianKey(digits string) (string, error) {
+	rem, err := Mod97(digits)
+	if err != nil {
+		return "", err
+	}
+	if rem == 0 {
+		rem = 97
+	}
+	return fmt.Sprintf("%02d", rem), nil
+}
+
+// spanishDC computes a 'dígito de control' of 10 digits.
+func spanishDC(digits string) string {
+	weights := []int{1, 2, 4, 8, 5, 10, 9, 7, 3, 6}
+	sum := 0
+	for i, w := range weights {
+		sum += int(digits[i]-'0') * w
+	}
+	switch dc := 11 - sum%11; dc {
+	case 11:
+		return "0"
+	case 10:
+		return "1"
+	default:
+		return strconv.Itoa(dc)
+	}
+}
+
+// italianCIN computes the control letter of an italian ABI, CAB and account.
+func italianCIN(s string) string {
+	odd := []int{1, 0, 5, 7, 9, 13, 15, 17, 19, 21, 2, 4, 18, 20, 11, 3, 6, 8, 12, 14, 16, 10, 22, 25, 24, 23}
+	sum := 0
+	for i, r := range s {
+		v := int(r - 'A')
+		if r >= '0' && r <= '9' {
+			v = int(r - '0')
+		}
+		if i%2 == 0 {
+			v = odd[v]
+		}
+		sum += v
+	}
+	return string(rune('A' + sum%26))
+}
+
+// portugueseNIB computes the key of a portuguese bank, branch and account.
+func portugueseNIB(digits string) (string, error) {
+	rem, err := Mod97(digits + "00")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%02d", 98-rem), nil
+}
+
+// ibanNationalKeys set the national check key of BBANs, for countries whose
+// BBAN has one that does not depend on the bank.
+var ibanNationalKeys = map[string]func(bban string) (string, error){
+	"BE": func(bban string) (string, error) {
+		key, err := belgianKey(bban[:10])
+		return bban[:10] + key, err
+	},
+	"ES": func(bban string) (string, error) {
+		return bban[:8] + spanishDC("00"+bban[:8]) + spanishDC(bban[10:]) + bban[10:], nil
+	},
+	"FR": func(bban string) (string, error) {
+		key, err := frenchRIBKey(bban[0:5], bban[5:10], bban[10:21])
+		return bban[:21] + key, err
+	},
+	"IT": func(bban string) (string, error) {
+		return italianCIN(bban[1:]) + bban[1:], nil
+	},
+	"PT": func(bban string) (string, error) {
+		key, err := portugueseNIB(bban[:19])
+		return bban[:19] + key, err
+	},
+}
+
+// BBANKeyValid reports whether the national check key of a BBAN is valid,
+// BBANs of countries without such key always being valid.
+func BBANKeyValid(country, bban string) bool {
+	setKey, ok := ibanNationalKeys[country]
+	if !ok {
+		return true
+	}
+	if layout, ok := IBANLayouts[country]; !ok || len(bban) != bbanLength(layout) {
+		return false
+	}
+	expected, err := setKey(bban)
+	return err == nil && expected == bban
+}
+
+// bbanLength returns the number of characters of a BBAN layout.
+func bbanLength(layout string) int {
+	ret, count := 0, 0
+	for _, r := range layout {
+		if r >= '0' && r <= '9' {
+			count = count*10 + int(r-'0')
+			continue
+		}
+		ret += count
+		count = 0
+	}
+	return ret
+}
+
+// IBANCheckDigits computes the two check digits of an IBAN.
+func IBANCheckDigits(country, bban string) (string, error) {
+	rem, err := Mod97(bban + country + "00")
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%02d", 98-rem), nil
+}
+
+// IBANValid reports whether an IBAN, spaces allowed, has valid check digits.
+func IBANValid(iban string) bool {
+	iban = strings.ReplaceAll(iban, " ", "")
+	if len(iban) < 5 {
+		return false
+	}
+	rem, err := Mod97(iban[4:] + iban[:4])
+	return err == nil && rem == 1
+}
+
+// RandIBAN returns a valid IBAN for a country of IBANLayouts, national check
+// keys included.
+func RandIBAN(rng *rand.Rand, country string) (string, error) {
+	layout, ok := IBANLayouts[country]
+	if !ok {
+		return "", fmt.Errorf("unsupported IBAN country '%s'", country)
+	}
+	bban, err := randBBAN(rng, layout)
+	if err != nil {
+		return "", err
+	}
+	if setKey, ok := ibanNationalKeys[country]; ok {
+		if bban, err = setKey(bban); err != nil {
+			return "", err
+		}
+	}
+	check, err := IBANCheckDigits(country, bban)
+	if err != nil {
+		return "", err
+	}
+	return country + check + bban, nil
+}
+
+// RandCardNumber returns a Luhn-valid card number for a network of CardNetworks.
+func RandCardNumber(rng *rand.Rand, network string) (string, error) {
+	n, ok := CardNetworks[network]
+	if !ok {
+		return "", fmt.Errorf("unsupported card network '%s'", network)
+	}
+	prefixes := n.Prefixes[rng.IntN(len(n.Prefixes))]
+	prefix := strconv.Itoa(prefixes[0] + rng.IntN(prefixes[1]-prefixes[0]+1))
+	digits := prefix + randDigits(rng, n.Length-len(prefix)-1)
+	return digits + strconv.Itoa(LuhnCheckDigit(digits)), nil
+}
+
+type IBANGenerator struct {
+	*CacheGenerator
+
+	countries []string
+}
+
+// ParseIBAN parses 'FR|DE;format=print', an empty country list meaning
+// every country of IBANLayouts.
+func ParseIBAN(params ...any) (countries []string, grouped bool, err error) {
+	spec, opts, err := ParseSpecOptions(IBANOptions, params...)
+	if err != nil {
+		return nil, false, err
+	}
+	if grouped, err = parseGroupedFormat(opts); err != nil {
+		return nil, false, err
+	}
+	if len(spec) == 0 {
+		return slices.Sorted(maps.Keys(IBANLayouts)), grouped, nil
+	}
+	for _, country := range strings.Split(spec, "|") {
+		country = strings.ToUpper(strings.TrimSpace(country))
+		if _, ok := IBANLayouts[country]; !ok {
+			return nil, false, fmt.Errorf("unsupported IBAN country '%s'", country)
+		}
+		countries = append(countries, country)
+	}
+	return countries, grouped, nil
+}
+
+// parseGroupedFormat reads the 'format' option, either 'compact' or 'print'.
+func parseGroupedFormat(opts map[string]string) (bool, error) {
+	switch strings.ToLower(opts["format"]) {
+	case "", "compact":
+		return false, nil
+	case "print":
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid format '%s', expected 'compact' or 'print'", opts["format"])
+}
+
+func NewIBANGenerator(options *generator.GeneratorOptions, countries []string, grouped bool) *IBANGenerator {
+	return &IBANGenerator{
+		CacheGenerator: NewCacheGenerator(options, IBAN_GENERATOR_NAME, func() (string, error) {
+			iban, err := RandIBAN(options.Rand, countries[options.Rand.IntN(len(countries))])
+			if err != nil || !grouped {
+				return iban, err
+			}
+			return groupBy(iban, 4), nil
+		}),
+		countries: countries,
+	}
+}
+
+type CreditCardGenerator struct {
+	*CacheGenerator
+
+	networks []string
+}
+
+// ParseCreditCard parses 'visa|mastercard;format=print', an empty network
+// list meaning every network of CardNetworks.
+func ParseCreditCard(params ...any) (networks []string, grouped bool, err error) {
+	spec, opts, err := ParseSpecOptions(CreditCardOptions, params...)
+	if err != nil {
+		return nil, false, err
+	}
+	if grouped, err = parseGroupedFormat(opts); err != nil {
+		return nil, false, err
+	}
+	if len(spec) == 0 {
+		return slices.Sorted(maps.Keys(CardNetworks)), grouped, nil
+	}
+	for _, network := range strings.Split(spec, "|") {
+		network = strings.ToLower(strings.TrimSpace(network))
+		if _, ok := CardNetworks[network]; !ok {
+			return nil, false, fmt.Errorf("unsupported card network '%s'", network)
+		}
+		networks = append(networks, network)
+	}
+	return networks, grouped, nil
+}
+
+func NewCreditCardGenerator(options *generator.GeneratorOptions, networks []string, grouped bool) *CreditCardGenerator {
+	return &CreditCardGenerator{
+		CacheGenerator: NewCacheGenerator(options, CREDIT_CARD_GENERATOR_NAME, func() (string, error) {
+			number, err := RandCardNumber(options.Rand, networks[options.Rand.IntN(len(networks))])
+			if err != nil || !grouped {
+				return number, err
+			}
+			return groupBy(number, 4), nil
+		}),
+		networks: networks,
+	}
+}
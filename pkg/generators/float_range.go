@@ -0,0 +1,17 @@
+package generators
+
+import "github.com/welschmorgan/datagen/pkg/generator"
+
+const FLOAT_RANGE_GENERATOR_NAME = "float_range"
+
+type FloatRangeGenerator struct {
+	*CacheGenerator
+}
+
+func NewFloatRangeGenerator(options *generator.GeneratorOptions, range_ *FloatRange) *FloatRangeGenerator {
+	return &FloatRangeGenerator{
+		CacheGenerator: NewCacheGenerator(options, FLOAT_RANGE_GENERATOR_NAME, func() (string, error) {
+			return range_.RandPadded(options.Rand), nil
+		}),
+	}
+}
@@ -0,0 +1,366 @@
+package generators
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const GEO_GENERATOR_NAME = "geo"
+
+const GEO_DEFAULT_PRECISION = 6
+const GEO_EARTH_RADIUS_KM = 6371.0088
+const GEO_MAX_POLYGON_ATTEMPTS = 10000
+
+var GeoOptions = []string{"precision", "format"}
+
+type GeoFormat int64
+
+const (
+	GeoFormatLatLon GeoFormat = iota
+	GeoFormatWKT
+	GeoFormatGeoJSON
+)
+
+type GeoPoint struct {
+	Lat float64
+	Lon float64
+}
+
+// GeoArea is a region of the globe points can be drawn from.
+type GeoArea interface {
+	Rand(rng *rand.Rand) (GeoPoint, error)
+	Contains(p GeoPoint) bool
+}
+
+// GeoBBox is a latitude/longitude bounding box.
+type GeoBBox struct {
+	Min GeoPoint
+	Max GeoPoint
+}
+
+func (b *GeoBBox) Contains(p GeoPoint) bool {
+	return p.Lat >= b.Min.Lat && p.Lat <= b.Max.Lat && p.Lon >= b.Min.Lon && p.Lon <= b.Max.Lon
+}
+
+// Rand draws a point uniformly over the surface of the box, latitudes being
+// weighted by the sine so that points don't cluster towards the poles.
+func (b *GeoBBox) Rand(rng *rand.Rand) (GeoPoint, error) {
+	lo, hi := math.Sin(b.Min.Lat*math.Pi/180), math.Sin(b.Max.Lat*math.Pi/180)
+	return GeoPoint{
+		Lat: math.Asin(lo+rng.Float64()*(hi-lo)) * 180 / math.Pi,
+		Lon: b.Min.Lon + rng.Float64()*(b.Max.Lon-b.Min.Lon),
+	}, nil
+}
+
+// GeoRadius is the disc of Radius kilometers around Center.
+type GeoRadius struct {
+	Center GeoPoint
+	Radius float64
+}
+
+func (r *GeoRadius) Contains(p GeoPoint) bool {
+	return GeoDistance(r.Center, p) <= r.Radius
+}
+
+// Rand draws a distance weighted by its square root and a uniform bearing,
+// then walks the great circle from the center.
+func (r *GeoRadius) Rand(rng *rand.Rand) (GeoPoint, error) {
+	dist := r.Radius * math.Sqrt(rng.Float64()) / GEO_EARTH_RADIUS_KM
+	bearing := rng.Float64() * 2 * math.Pi
+	lat1, lon1 := r.Center.Lat*math.Pi/180, r.Center.Lon*math.Pi/180
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(dist) + math.Cos(lat1)*math.Sin(dist)*math.Cos(bearing))
+	lon2 := lon1 + math.Atan2(math.Sin(bearing)*math.Sin(dist)*math.Cos(lat1), math.Cos(dist)-math.Sin(lat1)*math.Sin(lat2))
+	lon := math.Mod(lon2*180/math.Pi+540, 360) - 180
+	return GeoPoint{Lat: lat2 * 180 / math.Pi, Lon: lon}, nil
+}
+
+// GeoDistance returns the haversine distance between two points in kilometers.
+func GeoDistance(a, b GeoPoint) float64 {
+	lat1, lat2 := a.Lat*math.Pi/180, b.Lat*math.Pi/180
+	dLat, dLon := lat2-lat1, (b.Lon-a.Lon)*math.Pi/180
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLon/2), 2)
+	return 2 * GEO_EARTH_RADIUS_KM * math.Asin(math.Sqrt(h))
+}
+
+// GeoPolygons is a set of polygons, each made of an outer ring followed by
+// its holes, as in GeoJSON.
+type GeoPolygons struct {
+	Polygons [][][]GeoPoint
+	bbox     GeoBBox
+}
+
+func NewGeoPolygons(polygons [][][]GeoPoint) (*GeoPolygons, error) {
+	ret := &GeoPolygons{
+		Polygons: polygons,
+		bbox:     GeoBBox{Min: GeoPoint{90, 180}, Max: GeoPoint{-90, -180}},
+	}
+	if len(polygons) == 0 {
+		return nil, fmt.Errorf("invalid polygon, no ring found")
+	}
+	for _, polygon := range polygons {
+		if len(polygon) == 0 || len(polygon[0]) < 4 {
+			return nil, fmt.Errorf("invalid polygon, outer ring needs at least 4 positions")
+		}
+		for _, p := range polygon[0] {
+			ret.bbox.Min.Lat, ret.bbox.Max.Lat = math.Min(ret.bbox.Min.Lat, p.Lat), math.Max(ret.bbox.Max.Lat, p.Lat)
+			ret.bbox.Min.Lon, ret.bbox.Max.Lon = math.Min(ret.bbox.Min.Lon, p.Lon), math.Max(ret.bbox.Max.Lon, p.Lon)
+		}
+	}
+	return ret, nil
+}
+
+// ringContains casts a ray along the latitude of p, counting crossed edges.
+func ringContains(ring []GeoPoint, p GeoPoint) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) && p.Lon < (b.Lon-a.Lon)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func (g *GeoPolygons) Contains(p GeoPoint) bool {
+	for _, polygon := range g.Polygons {
+		if !ringContains(polygon[0], p) {
+			continue
+		}
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if ringContains(hole, p) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// Rand draws points in the bounding box of the polygons until one falls inside.
+func (g *GeoPolygons) Rand(rng *rand.Rand) (GeoPoint, error) {
+	for range GEO_MAX_POLYGON_ATTEMPTS {
+		p, _ := g.bbox.Rand(rng)
+		if g.Contains(p) {
+			return p, nil
+		}
+	}
+	return GeoPoint{}, fmt.Errorf("failed to draw a point inside polygon after %d attempts", GEO_MAX_POLYGON_ATTEMPTS)
+}
+
+type geoJSONObject struct {
+	Type        string           `json:"type"`
+	Coordinates json.RawMessage  `json:"coordinates"`
+	Geometry    *geoJSONObject   `json:"geometry"`
+	Features    []*geoJSONObject `json:"features"`
+}
+
+func geoRings(rings [][][2]float64) [][]GeoPoint {
+	ret := [][]GeoPoint{}
+	for _, ring := range rings {
+		points := []GeoPoint{}
+		for _, pos := range ring {
+			points = append(points, GeoPoint{Lat: pos[1], Lon: pos[0]})
+		}
+		ret = append(ret, points)
+	}
+	return ret
+}
+
+func (o *geoJSONObject) polygons() ([][][]GeoPoint, error) {
+	switch o.Type {
+	case "Polygon":
+		var rings [][][2]float64
+		if err := json.Unmarshal(o.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("invalid GeoJSON polygon, %s", err)
+		}
+		return [][][]GeoPoint{geoRings(rings)}, nil
+	case "MultiPolygon":
+		var polygons [][][][2]float64
+		if err := json.Unmarshal(o.Coordinates, &polygons); err != nil {
+			return nil, fmt.Errorf("invalid GeoJSON multi-polygon, %s", err)
+		}
+		ret := [][][]GeoPoint{}
+		for _, rings := range polygons {
+			ret = append(ret, geoRings(rings))
+		}
+		return ret, nil
+	case "Feature":
+		if o.Geometry == nil {
+			return nil, fmt.Errorf("invalid GeoJSON feature, missing geometry")
+		}
+		return o.Geometry.polygons()
+	case "FeatureCollection":
+		ret := [][][]GeoPoint{}
+		for _, feature := range o.Features {
+			polygons, err := feature.polygons()
+			if err != nil {
+				return nil, err
+			}
+			ret = append(ret, polygons...)
+		}
+		return ret, nil
+	}
+	return nil, fmt.Errorf("unsupported GeoJSON type '%s', expected a polygon, multi-polygon, feature or feature collection", o.Type)
+}
+
+// ParseGeoJSON reads the polygons of a GeoJSON geometry, feature or feature collection.
+func ParseGeoJSON(data []byte) (*GeoPolygons, error) {
+	obj := &geoJSONObject{}
+	if err := json.Unmarshal(data, obj); err != nil {
+		return nil, fmt.Errorf("invalid GeoJSON, %s", err)
+	}
+	polygons, err := obj.polygons()
+	if err != nil {
+		return nil, err
+	}
+	return NewGeoPolygons(polygons)
+}
+
+// parseGeoCall splits 'name(a,b,c)' into its name and float arguments.
+func parseGeoCall(spec string) (string, []float64, error) {
+	open := strings.Index(spec, "(")
+	if open < 0 || !strings.HasSuffix(spec, ")") {
+		return "", nil, fmt.Errorf("invalid geo area '%s', expected 'name(args)'", spec)
+	}
+	name := strings.ToLower(strings.TrimSpace(spec[:open]))
+	args := []float64{}
+	if name == "file" {
+		return name, nil, nil
+	}
+	for _, arg := range strings.Split(spec[open+1:len(spec)-1], ",") {
+		v, err := strconv.ParseFloat(strings.TrimSpace(arg), 64)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid geo area '%s', %s", spec, err)
+		}
+		args = append(args, v)
+	}
+	return name, args, nil
+}
+
+func validGeoPoint(p GeoPoint) error {
+	if p.Lat < -90 || p.Lat > 90 || p.Lon < -180 || p.Lon > 180 {
+		return fmt.Errorf("invalid coordinates %v,%v, expected latitude in [-90,90] and longitude in [-180,180]", p.Lat, p.Lon)
+	}
+	return nil
+}
+
+// ParseGeoArea parses one of:
+//   - 'bbox(minLat,minLon,maxLat,maxLon)'
+//   - 'radius(lat,lon,km)'
+//   - 'file(path.geojson)'
+//   - an inline GeoJSON object
+func ParseGeoArea(spec string) (GeoArea, error) {
+	if strings.HasPrefix(spec, "{") {
+		return ParseGeoJSON([]byte(spec))
+	}
+	name, args, err := parseGeoCall(spec)
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "bbox":
+		if len(args) != 4 {
+			return nil, fmt.Errorf("invalid bbox '%s', expected 'bbox(minLat,minLon,maxLat,maxLon)'", spec)
+		}
+		ret := &GeoBBox{Min: GeoPoint{args[0], args[1]}, Max: GeoPoint{args[2], args[3]}}
+		for _, p := range []GeoPoint{ret.Min, ret.Max} {
+			if err := validGeoPoint(p); err != nil {
+				return nil, err
+			}
+		}
+		if ret.Min.Lat > ret.Max.Lat || ret.Min.Lon > ret.Max.Lon {
+			return nil, fmt.Errorf("invalid bbox '%s', minimum is greater than maximum", spec)
+		}
+		return ret, nil
+	case "radius":
+		if len(args) != 3 {
+			return nil, fmt.Errorf("invalid radius '%s', expected 'radius(lat,lon,km)'", spec)
+		}
+		ret := &GeoRadius{Center: GeoPoint{args[0], args[1]}, Radius: args[2]}
+		if err := validGeoPoint(ret.Center); err != nil {
+			return nil, err
+		}
+		if ret.Radius <= 0 {
+			return nil, fmt.Errorf("invalid radius '%s', expected a positive distance", spec)
+		}
+		return ret, nil
+	case "file":
+		path := strings.TrimSpace(spec[strings.Index(spec, "(")+1 : len(spec)-1])
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read GeoJSON file '%s', %s", path, err)
+		}
+		return ParseGeoJSON(data)
+	}
+	return nil, fmt.Errorf("unknown geo area '%s', expected one of bbox, radius or file", name)
+}
+
+// ParseGeo parses 'area;precision=N;format=latlon|wkt|geojson'.
+func ParseGeo(params ...any) (area GeoArea, precision int, format GeoFormat, err error) {
+	spec, opts, err := ParseSpecOptions(GeoOptions, params...)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if area, err = ParseGeoArea(spec); err != nil {
+		return nil, 0, 0, err
+	}
+	precision = GEO_DEFAULT_PRECISION
+	if v, ok := opts["precision"]; ok {
+		if precision, err = strconv.Atoi(v); err != nil || precision < 0 || precision > 15 {
+			return nil, 0, 0, fmt.Errorf("invalid geo precision '%s', expected a number between 0 and 15", v)
+		}
+	}
+	switch strings.ToLower(opts["format"]) {
+	case "", "latlon":
+		format = GeoFormatLatLon
+	case "wkt":
+		format = GeoFormatWKT
+	case "geojson":
+		format = GeoFormatGeoJSON
+	default:
+		return nil, 0, 0, fmt.Errorf("invalid geo format '%s', expected one of latlon, wkt or geojson", opts["format"])
+	}
+	return area, precision, format, nil
+}
+
+// FormatGeoPoint renders p with the given number of decimals.
+func FormatGeoPoint(p GeoPoint, precision int, format GeoFormat) string {
+	lat, lon := strconv.FormatFloat(p.Lat, 'f', precision, 64), strconv.FormatFloat(p.Lon, 'f', precision, 64)
+	switch format {
+	case GeoFormatWKT:
+		return fmt.Sprintf("POINT(%s %s)", lon, lat)
+	case GeoFormatGeoJSON:
+		return fmt.Sprintf(`{"type":"Point","coordinates":[%s,%s]}`, lon, lat)
+	}
+	return lat + "," + lon
+}
+
+type GeoGenerator struct {
+	*CacheGenerator
+
+	area GeoArea
+}
+
+func NewGeoGenerator(options *generator.GeneratorOptions, area GeoArea, precision int, format GeoFormat) *GeoGenerator {
+	return &GeoGenerator{
+		CacheGenerator: NewCacheGenerator(options, GEO_GENERATOR_NAME, func() (string, error) {
+			p, err := area.Rand(options.Rand)
+			if err != nil {
+				return "", err
+			}
+			return FormatGeoPoint(p, precision, format), nil
+		}),
+		area: area,
+	}
+}
@@ -0,0 +1,49 @@
+package generators_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestGeoAreas(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	for _, spec := range []string{
+		"bbox(42.3,-4.8,51.1,8.2)",
+		"radius(48.8566,2.3522,5)",
+		`{"type":"Feature","geometry":{"type":"Polygon","coordinates":[[[0,0],[10,0],[10,10],[0,10],[0,0]],[[4,4],[6,4],[6,6],[4,6],[4,4]]]}}`,
+	} {
+		area, err := generators.ParseGeoArea(spec)
+		if err != nil {
+			t.Fatalf("failed to parse geo area '%s', %s", spec, err)
+		}
+		for range 200 {
+			p, err := area.Rand(src)
+			if err != nil {
+				t.Fatalf("failed to draw point in '%s', %s", spec, err)
+			}
+			if !area.Contains(p) {
+				t.Errorf("point %+v is outside of '%s'", p, spec)
+			}
+		}
+	}
+	for _, spec := range []string{"bbox(1,2,3)", "radius(95,0,1)", "radius(0,0,-1)", "circle(0,0,1)", `{"type":"Point","coordinates":[0,0]}`} {
+		if _, err := generators.ParseGeoArea(spec); err == nil {
+			t.Errorf("expected an error for geo area '%s'", spec)
+		}
+	}
+}
+
+func TestFormatGeoPoint(t *testing.T) {
+	p := generators.GeoPoint{Lat: 48.856614, Lon: 2.3522219}
+	for format, expected := range map[generators.GeoFormat]string{
+		generators.GeoFormatLatLon:  "48.857,2.352",
+		generators.GeoFormatWKT:     "POINT(2.352 48.857)",
+		generators.GeoFormatGeoJSON: `{"type":"Point","coordinates":[2.352,48.857]}`,
+	} {
+		if got := generators.FormatGeoPoint(p, 3, format); got != expected {
+			t.Errorf("expected '%s', got '%s'", expected, got)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+package generators
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const UUID_GENERATOR_NAME = "uuid"
+const ULID_GENERATOR_NAME = "ulid"
+const SNOWFLAKE_GENERATOR_NAME = "snowflake"
+
+var SnowflakeOptions = []string{"epoch", "node"}
+
+// SNOWFLAKE_DEFAULT_EPOCH is the epoch used by twitter snowflakes (2010-11-04).
+const SNOWFLAKE_DEFAULT_EPOCH int64 = 1288834974657
+
+const (
+	snowflakeNodeBits     = 10
+	snowflakeSequenceBits = 12
+	snowflakeMaxNode      = 1<<snowflakeNodeBits - 1
+	snowflakeMaxSequence  = 1<<snowflakeSequenceBits - 1
+)
+
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+func randomBytes(rng *rand.Rand, b []byte) {
+	for i := 0; i < len(b); i += 8 {
+		var chunk [8]byte
+		binary.LittleEndian.PutUint64(chunk[:], rng.Uint64())
+		copy(b[i:], chunk[:])
+	}
+}
+
+func formatUUID(b [16]byte) string {
+	h := hex.EncodeToString(b[:])
+	return fmt.Sprintf("%s-%s-%s-%s-%s", h[0:8], h[8:12], h[12:16], h[16:20], h[20:32])
+}
+
+// NewUUIDv4 returns a random (version 4) UUID.
+func NewUUIDv4(rng *rand.Rand) [16]byte {
+	var b [16]byte
+	randomBytes(rng, b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return b
+}
+
+// NewUUIDv7 returns a time-ordered (version 7) UUID, seq fills the 12 bits
+// following the timestamp to keep ordering within a millisecond.
+func NewUUIDv7(rng *rand.Rand, ms int64, seq uint16) [16]byte {
+	var b [16]byte
+	randomBytes(rng, b[:])
+	b[0] = byte(ms >> 40)
+	b[1] = byte(ms >> 32)
+	b[2] = byte(ms >> 24)
+	b[3] = byte(ms >> 16)
+	b[4] = byte(ms >> 8)
+	b[5] = byte(ms)
+	b[6] = 0x70 | byte(seq>>8)&0x0f
+	b[7] = byte(seq)
+	b[8] = (b[8] & 0x3f) | 0x80
+	return b
+}
+
+type UUIDGenerator struct {
+	*CacheGenerator
+
+	version int
+
+	mu     sync.Mutex
+	lastMs int64
+	seq    uint16
+}
+
+func ParseUUIDVersion(params ...any) (int, error) {
+	spec, _, err := ParseSpecOptions([]string{}, params...)
+	if err != nil {
+		return 0, err
+	}
+	switch strings.ToLower(spec) {
+	case "", "4", "v4":
+		return 4, nil
+	case "7", "v7":
+		return 7, nil
+	}
+	return 0, fmt.Errorf("unsupported uuid version '%s', expected 'v4' or 'v7'", spec)
+}
+
+func NewUUIDGenerator(options *generator.GeneratorOptions, version int) *UUIDGenerator {
+	ret := &UUIDGenerator{version: version}
+	ret.CacheGenerator = NewCacheGenerator(options, UUID_GENERATOR_NAME, ret.next)
+	return ret
+}
+
+func (g *UUIDGenerator) next() (string, error) {
+	if g.version == 4 {
+		return formatUUID(NewUUIDv4(g.options.Rand)), nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ms := g.options.Now().UnixMilli()
+	if ms <= g.lastMs {
+		// counter overflow borrows the next millisecond
+		g.seq++
+		if g.seq > 0x0fff {
+			g.seq = 0
+			g.lastMs++
+		}
+		ms = g.lastMs
+	} else {
+		g.seq = uint16(g.options.Rand.IntN(0x0800))
+		g.lastMs = ms
+	}
+	return formatUUID(NewUUIDv7(g.options.Rand, ms, g.seq)), nil
+}
+
+// EncodeULID renders a 48 bits timestamp followed by 80 bits of entropy as
+// 26 crockford base32 characters.
+func EncodeULID(ms int64, entropy [10]byte) string {
+	var b [16]byte
+	binary.BigEndian.PutUint16(b[0:], uint16(ms>>32))
+	binary.BigEndian.PutUint32(b[2:], uint32(ms))
+	copy(b[6:], entropy[:])
+	hi := binary.BigEndian.Uint64(b[0:8])
+	lo := binary.BigEndian.Uint64(b[8:16])
+	out := make([]byte, 26)
+	for i := 25; i >= 0; i-- {
+		out[i] = crockfordAlphabet[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out)
+}
+
+type ULIDGenerator struct {
+	*CacheGenerator
+
+	mu      sync.Mutex
+	lastMs  int64
+	entropy [10]byte
+}
+
+func NewULIDGenerator(options *generator.GeneratorOptions) *ULIDGenerator {
+	ret := &ULIDGenerator{}
+	ret.CacheGenerator = NewCacheGenerator(options, ULID_GENERATOR_NAME, ret.next)
+	return ret
+}
+
+func (g *ULIDGenerator) next() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ms := g.options.Now().UnixMilli()
+	if ms <= g.lastMs {
+		// monotonic ULIDs increment the entropy within the same millisecond
+		ms = g.lastMs
+		overflow := true
+		for i := len(g.entropy) - 1; i >= 0; i-- {
+			g.entropy[i]++
+			if g.entropy[i] != 0 {
+				overflow = false
+				break
+			}
+		}
+		if overflow {
+			// entropy overflow borrows the next millisecond
+			g.lastMs++
+			ms = g.lastMs
+			randomBytes(g.options.Rand, g.entropy[:])
+		}
+	} else {
+		randomBytes(g.options.Rand, g.entropy[:])
+		g.lastMs = ms
+	}
+	return EncodeULID(ms, g.entropy), nil
+}
+
+type SnowflakeGenerator struct {
+	*CacheGenerator
+
+	epoch int64
+	node  int64
+
+	mu     sync.Mutex
+	lastMs int64
+	seq    int64
+}
+
+// ParseSnowflake parses ';epoch=2015-01-01;node=12', the epoch being either a
+// date or a unix timestamp in milliseconds.
+func ParseSnowflake(params ...any) (epoch int64, node int64, err error) {
+	_, opts, err := ParseSpecOptions(SnowflakeOptions, params...)
+	if err != nil {
+		return 0, 0, err
+	}
+	epoch = SNOWFLAKE_DEFAULT_EPOCH
+	if v, ok := opts["epoch"]; ok {
+		if epoch, err = strconv.ParseInt(v, 10, 64); err != nil {
+			t, err := time.Parse(DATETIME_LAYOUT_DATE, v)
+			if err != nil {
+				return 0, 0, fmt.Errorf("invalid snowflake epoch '%s', expected a date or milliseconds", v)
+			}
+			epoch = t.UnixMilli()
+		}
+	}
+	if v, ok := opts["node"]; ok {
+		if node, err = strconv.ParseInt(v, 10, 64); err != nil || node < 0 || node > snowflakeMaxNode {
+			return 0, 0, fmt.Errorf("invalid snowflake node '%s', expected a number between 0 and %d", v, snowflakeMaxNode)
+		}
+	}
+	return epoch, node, nil
+}
+
+func NewSnowflakeGenerator(options *generator.GeneratorOptions, epoch, node int64) *SnowflakeGenerator {
+	ret := &SnowflakeGenerator{epoch: epoch, node: node}
+	ret.CacheGenerator = NewCacheGenerator(options, SNOWFLAKE_GENERATOR_NAME, ret.next)
+	return ret
+}
+
+func (g *SnowflakeGenerator) next() (string, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	ms := g.options.Now().UnixMilli()
+	if ms < g.epoch {
+		return "", fmt.Errorf("invalid snowflake epoch %d, it is in the future", g.epoch)
+	}
+	if ms <= g.lastMs {
+		ms = g.lastMs
+		g.seq = (g.seq + 1) & snowflakeMaxSequence
+		if g.seq == 0 {
+			ms++
+		}
+	} else {
+		g.seq = 0
+	}
+	g.lastMs = ms
+	id := (ms-g.epoch)<<(snowflakeNodeBits+snowflakeSequenceBits) | g.node<<snowflakeSequenceBits | g.seq
+	return strconv.FormatInt(id, 10), nil
+}
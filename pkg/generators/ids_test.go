@@ -0,0 +1,68 @@
+package generators_test
+
+import (
+	"math/rand/v2"
+	"regexp"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestUUIDGenerator(t *testing.T) {
+	patterns := map[int]*regexp.Regexp{
+		4: regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+		7: regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`),
+	}
+	for version, pattern := range patterns {
+		g := generators.NewUUIDGenerator(generator.NewGeneratorOptions(), version)
+		prev := ""
+		for range 100 {
+			v, err := g.Next()
+			if err != nil {
+				t.Fatalf("failed to generate uuid v%d, %s", version, err)
+			}
+			if !pattern.MatchString(v) {
+				t.Errorf("invalid uuid v%d '%s'", version, v)
+			}
+			if version == 7 && v <= prev {
+				t.Errorf("uuid v7 are not ordered, '%s' <= '%s'", v, prev)
+			}
+			prev = v
+		}
+	}
+}
+
+func TestEncodeULID(t *testing.T) {
+	entropy := [10]byte{}
+	if v := generators.EncodeULID(0, entropy); v != "00000000000000000000000000" {
+		t.Errorf("invalid ulid for zero value, got '%s'", v)
+	}
+	entropy[9] = 1
+	if v := generators.EncodeULID(1, entropy); v != "00000000010000000000000001" {
+		t.Errorf("invalid ulid, got '%s'", v)
+	}
+}
+
+// saturatedSource always yields set bits, so that ULID entropy overflows.
+type saturatedSource struct{}
+
+func (saturatedSource) Uint64() uint64 {
+	return ^uint64(0)
+}
+
+func TestULIDEntropyOverflow(t *testing.T) {
+	options := generator.NewGeneratorOptions()
+	options.SetRandSeed(1)
+	options.Rand = rand.New(saturatedSource{})
+	g := generators.NewULIDGenerator(options)
+	first, _ := g.Next()
+	second, _ := g.Next()
+	if second <= first {
+		t.Errorf("ulids are not ordered on entropy overflow, '%s' <= '%s'", second, first)
+	}
+	var entropy [10]byte
+	if next := generators.EncodeULID(generator.SEEDED_NOW.UnixMilli()+1, entropy); second[:10] != next[:10] {
+		t.Errorf("expected entropy overflow to borrow the next millisecond, got '%s' after '%s'", second, first)
+	}
+}
@@ -0,0 +1,302 @@
+package generators
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const IMAGE_GENERATOR_NAME = "image"
+
+const IMAGE_DEFAULT_SIZE = 64
+const IMAGE_MAX_SIZE = 2048
+const IMAGE_JPEG_QUALITY = 90
+
+// IDENTICON_GRID is the number of cells per side of identicons, the left
+// half being mirrored to the right.
+const IDENTICON_GRID = 5
+
+var ImageOptions = []string{"size", "source", "format", "output", "dir"}
+
+type ImageKind string
+
+const (
+	ImageKindIdenticon ImageKind = "identicon"
+	ImageKindSolid     ImageKind = "solid"
+	ImageKindGradient  ImageKind = "gradient"
+)
+
+type ImageSettings struct {
+	Kind   ImageKind
+	Width  int
+	Height int
+	// Source is the resource whose values identicons are derived from
+	Source string
+	// Format is either 'png' or 'jpeg'
+	Format string
+	// Dir is where images are written, data URIs being returned when empty
+	Dir string
+}
+
+func parseImageSize(s string) (int, int, error) {
+	w, h, found := strings.Cut(strings.ToLower(s), "x")
+	if !found {
+		h = w
+	}
+	width, err := strconv.Atoi(strings.TrimSpace(w))
+	if err != nil || width < 1 || width > IMAGE_MAX_SIZE {
+		return 0, 0, fmt.Errorf("invalid image size '%s', expected 'N' or 'WxH' up to %d", s, IMAGE_MAX_SIZE)
+	}
+	height, err := strconv.Atoi(strings.TrimSpace(h))
+	if err != nil || height < 1 || height > IMAGE_MAX_SIZE {
+		return 0, 0, fmt.Errorf("invalid image size '%s', expected 'N' or 'WxH' up to %d", s, IMAGE_MAX_SIZE)
+	}
+	return width, height, nil
+}
+
+// ParseImage parses 'identicon|solid|gradient;size=WxH;source=resource;format=png|jpeg;output=datauri|file;dir=path'.
+func ParseImage(params ...any) (*ImageSettings, error) {
+	spec, opts, err := ParseSpecOptions(ImageOptions, params...)
+	if err != nil {
+		return nil, err
+	}
+	ret := &ImageSettings{Kind: ImageKind(strings.ToLower(spec)), Width: IMAGE_DEFAULT_SIZE, Height: IMAGE_DEFAULT_SIZE, Source: opts["source"], Format: "png"}
+	switch ret.Kind {
+	case ImageKindIdenticon:
+		if len(ret.Source) == 0 {
+			return nil, fmt.Errorf("invalid identicon, expected a 'source' resource")
+		}
+	case ImageKindSolid, ImageKindGradient:
+		if len(ret.Source) > 0 {
+			return nil, fmt.Errorf("invalid %s image, only identicons have a source", ret.Kind)
+		}
+	default:
+		return nil, fmt.Errorf("invalid image kind '%s', expected one of identicon, solid or gradient", spec)
+	}
+	if v, ok := opts["size"]; ok {
+		if ret.Width, ret.Height, err = parseImageSize(v); err != nil {
+			return nil, err
+		}
+	}
+	switch strings.ToLower(opts["format"]) {
+	case "", "png":
+	case "jpeg", "jpg":
+		ret.Format = "jpeg"
+	default:
+		return nil, fmt.Errorf("invalid image format '%s', expected 'png' or 'jpeg'", opts["format"])
+	}
+	switch strings.ToLower(opts["output"]) {
+	case "", "datauri":
+		if _, ok := opts["dir"]; ok {
+			return nil, fmt.Errorf("invalid image options, 'dir' requires 'output=file'")
+		}
+	case "file":
+		if ret.Dir = opts["dir"]; len(ret.Dir) == 0 {
+			ret.Dir = filepath.Join(os.TempDir(), "datagen", "images")
+		}
+	default:
+		return nil, fmt.Errorf("invalid image output '%s', expected 'datauri' or 'file'", opts["output"])
+	}
+	return ret, nil
+}
+
+// hslColor converts a hue in degrees, saturation and lightness in [0,1].
+func hslColor(h, s, l float64) color.RGBA {
+	c := (1 - math.Abs(2*l-1)) * s
+	hp := h / 60
+	x := c * (1 - math.Abs(math.Mod(hp, 2)-1))
+	var r, g, b float64
+	switch int(hp) % 6 {
+	case 0:
+		r, g = c, x
+	case 1:
+		r, g = x, c
+	case 2:
+		g, b = c, x
+	case 3:
+		g, b = x, c
+	case 4:
+		r, b = x, c
+	case 5:
+		r, b = c, x
+	}
+	m := l - c/2
+	return color.RGBA{uint8((r + m) * 255), uint8((g + m) * 255), uint8((b + m) * 255), 255}
+}
+
+func randColor(rng *rand.Rand) color.RGBA {
+	return hslColor(rng.Float64()*360, 0.4+rng.Float64()*0.4, 0.35+rng.Float64()*0.3)
+}
+
+// Identicon draws the symmetric pattern derived from the SHA-256 of value,
+// so that a value always gets the same picture.
+func Identicon(value string, width, height int) *image.RGBA {
+	sum := sha256.Sum256([]byte(value))
+	fg := hslColor(float64(int(sum[0])<<8|int(sum[1]))*360/65536, 0.45+float64(sum[2])/255*0.3, 0.4+float64(sum[3])/255*0.2)
+	bg := color.RGBA{240, 240, 240, 255}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := range height {
+		for x := range width {
+			col, row := x*IDENTICON_GRID/width, y*IDENTICON_GRID/height
+			if col > IDENTICON_GRID/2 {
+				col = IDENTICON_GRID - 1 - col
+			}
+			bit := row*(IDENTICON_GRID/2+1) + col
+			if sum[4+bit/8]&(1<<(bit%8)) != 0 {
+				img.SetRGBA(x, y, fg)
+			} else {
+				img.SetRGBA(x, y, bg)
+			}
+		}
+	}
+	return img
+}
+
+// Gradient draws a diagonal gradient from one color to another.
+func Gradient(from, to color.RGBA, width, height int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	lerp := func(a, b uint8, t float64) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*t)
+	}
+	for y := range height {
+		for x := range width {
+			t := float64(x+y) / float64(max(width+height-2, 1))
+			img.SetRGBA(x, y, color.RGBA{lerp(from.R, to.R, t), lerp(from.G, to.G, t), lerp(from.B, to.B, t), 255})
+		}
+	}
+	return img
+}
+
+// EncodeImage encodes img as png or jpeg.
+func EncodeImage(img image.Image, format string) ([]byte, error) {
+	buf := bytes.Buffer{}
+	var err error
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: IMAGE_JPEG_QUALITY})
+	} else {
+		err = png.Encode(&buf, img)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s image, %s", format, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// writeImage stores data in dir under its content hash, so identical images
+// share one file.
+func writeImage(dir, format string, data []byte) (string, error) {
+	sum := sha256.Sum256(data)
+	ext := map[string]string{"png": ".png", "jpeg": ".jpg"}[format]
+	path := filepath.Join(dir, hex.EncodeToString(sum[:16])+ext)
+	if _, err := os.Stat(path); err == nil {
+		return path, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create image directory '%s', %s", dir, err)
+	}
+	// write then rename, so that concurrent writers never expose partial files
+	tmp, err := os.CreateTemp(dir, ".image-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create image in '%s', %s", dir, err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return "", fmt.Errorf("failed to write image '%s', %s", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return "", fmt.Errorf("failed to write image '%s', %s", path, err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", fmt.Errorf("failed to write image '%s', %s", path, err)
+	}
+	return path, nil
+}
+
+type ImageGenerator struct {
+	*CacheGenerator
+
+	settings  *ImageSettings
+	resGetter func(name string) generator.Generator
+}
+
+func NewImageGenerator(options *generator.GeneratorOptions, settings *ImageSettings, resGetter func(name string) generator.Generator) *ImageGenerator {
+	ret := &ImageGenerator{
+		settings:  settings,
+		resGetter: resGetter,
+	}
+	ret.CacheGenerator = NewCacheGenerator(options, IMAGE_GENERATOR_NAME, ret.next)
+	return ret
+}
+
+func (g *ImageGenerator) Validate() error {
+	if len(g.settings.Source) > 0 && g.resGetter(g.settings.Source) == nil {
+		return fmt.Errorf("unknown image source resource '%s'", g.settings.Source)
+	}
+	return CheckReferenceCycles(g.References(), g.resGetter)
+}
+
+func (g *ImageGenerator) References() []string {
+	if len(g.settings.Source) == 0 {
+		return []string{}
+	}
+	return []string{g.settings.Source}
+}
+
+// Render draws an image, identicons being derived from value and placeholders
+// from rng.
+func (s *ImageSettings) Render(rng *rand.Rand, value string) image.Image {
+	switch s.Kind {
+	case ImageKindIdenticon:
+		return Identicon(value, s.Width, s.Height)
+	case ImageKindGradient:
+		return Gradient(randColor(rng), randColor(rng), s.Width, s.Height)
+	}
+	c := randColor(rng)
+	return Gradient(c, c, s.Width, s.Height)
+}
+
+func (g *ImageGenerator) next() (string, error) {
+	value := ""
+	if len(g.settings.Source) > 0 {
+		gen := g.resGetter(g.settings.Source)
+		if gen == nil {
+			return "", fmt.Errorf("unknown image source resource '%s'", g.settings.Source)
+		}
+		var err error
+		if value, err = SampleValue(gen); err != nil {
+			return "", err
+		}
+		if generator.IsNull(value) {
+			return value, nil
+		}
+	}
+	return g.settings.Output(g.options.Rand, value)
+}
+
+// Output renders then encodes an image, returning either its data URI or the
+// path it was written to.
+func (s *ImageSettings) Output(rng *rand.Rand, value string) (string, error) {
+	data, err := EncodeImage(s.Render(rng, value), s.Format)
+	if err != nil {
+		return "", err
+	}
+	if len(s.Dir) > 0 {
+		return writeImage(s.Dir, s.Format, data)
+	}
+	return fmt.Sprintf("data:image/%s;base64,%s", s.Format, base64.StdEncoding.EncodeToString(data)), nil
+}
@@ -0,0 +1,63 @@
+package generators_test
+
+import (
+	"bytes"
+	"image/png"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestIdenticon(t *testing.T) {
+	a, err := generators.EncodeImage(generators.Identicon("jane.doe@example.com", 40, 40), "png")
+	if err != nil {
+		t.Fatalf("failed to encode identicon, %s", err)
+	}
+	b, _ := generators.EncodeImage(generators.Identicon("jane.doe@example.com", 40, 40), "png")
+	c, _ := generators.EncodeImage(generators.Identicon("john.doe@example.com", 40, 40), "png")
+	if !bytes.Equal(a, b) {
+		t.Errorf("expected identical identicons for identical values")
+	}
+	if bytes.Equal(a, c) {
+		t.Errorf("expected different identicons for different values")
+	}
+	img, err := png.Decode(bytes.NewReader(a))
+	if err != nil || img.Bounds().Dx() != 40 || img.Bounds().Dy() != 40 {
+		t.Errorf("invalid identicon, %v", err)
+	}
+}
+
+func TestImageGenerator(t *testing.T) {
+	dir := t.TempDir()
+	settings, err := generators.ParseImage("image", "identicon;source=email;size=32x16;output=file;dir="+dir)
+	if err != nil {
+		t.Fatalf("failed to parse image settings, %s", err)
+	}
+	options := generator.NewGeneratorOptions()
+	email := generators.NewSequenceGenerator(options, mustParseSequence(t, "user%d@example.com"))
+	g := generators.NewImageGenerator(options, settings, func(name string) generator.Generator {
+		if name == "email" {
+			return email
+		}
+		return nil
+	})
+	path, err := g.Next()
+	if err != nil {
+		t.Fatalf("failed to generate image, %s", err)
+	}
+	if _, err := os.Stat(path); err != nil || !strings.HasPrefix(path, dir) || !strings.HasSuffix(path, ".png") {
+		t.Errorf("invalid image path '%s', %v", path, err)
+	}
+	settings, _ = generators.ParseImage("image", "gradient;format=jpeg")
+	if uri, _ := generators.NewImageGenerator(options, settings, nil).Next(); !strings.HasPrefix(uri, "data:image/jpeg;base64,") {
+		t.Errorf("invalid data URI '%.40s'", uri)
+	}
+	for _, tpl := range []string{"identicon", "solid;source=email", "photo", "solid;size=0", "solid;size=10x", "solid;format=gif", "solid;dir=/tmp"} {
+		if _, err := generators.ParseImage("image", tpl); err == nil {
+			t.Errorf("expected an error for image template '%s'", tpl)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package generators
+
+import (
+	"database/sql"
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const MARKOV_GENERATOR_NAME = "markov"
+
+const (
+	MARKOV_DEFAULT_ORDER      = 3
+	MARKOV_DEFAULT_MIN_LENGTH = 3
+	MARKOV_DEFAULT_MAX_LENGTH = 12
+	MARKOV_MAX_ATTEMPTS       = 1000
+)
+
+var MarkovOptions = []string{"order", "min", "max", "locale"}
+
+const markovBoundary rune = 0
+
+// MarkovChain is a character-level model, each state being the last 'order'
+// characters and transitions being weighted by their frequency.
+type MarkovChain struct {
+	order       int
+	transitions map[string]*markovTransitions
+	known       map[string]bool
+}
+
+type markovTransitions struct {
+	runes  []rune
+	counts []int
+	total  int
+}
+
+func (t *markovTransitions) add(r rune) {
+	for i, known := range t.runes {
+		if known == r {
+			t.counts[i]++
+			t.total++
+			return
+		}
+	}
+	t.runes = append(t.runes, r)
+	t.counts = append(t.counts, 1)
+	t.total++
+}
+
+func (t *markovTransitions) pick(rng *rand.Rand) rune {
+	n := rng.IntN(t.total)
+	for i, count := range t.counts {
+		if n < count {
+			return t.runes[i]
+		}
+		n -= count
+	}
+	return t.runes[len(t.runes)-1]
+}
+
+func NewMarkovChain(order int) *MarkovChain {
+	return &MarkovChain{
+		order:       order,
+		transitions: map[string]*markovTransitions{},
+		known:       map[string]bool{},
+	}
+}
+
+// Train adds a word to the model, words are compared case-insensitively.
+func (c *MarkovChain) Train(word string) {
+	word = strings.ToLower(strings.TrimSpace(word))
+	if len(word) == 0 {
+		return
+	}
+	c.known[word] = true
+	state := []rune(strings.Repeat(string(markovBoundary), c.order))
+	for _, r := range append([]rune(word), markovBoundary) {
+		key := string(state)
+		t, ok := c.transitions[key]
+		if !ok {
+			t = &markovTransitions{}
+			c.transitions[key] = t
+		}
+		t.add(r)
+		state = append(state[1:], r)
+	}
+}
+
+func (c *MarkovChain) Len() int {
+	return len(c.known)
+}
+
+func (c *MarkovChain) Contains(word string) bool {
+	return c.known[strings.ToLower(word)]
+}
+
+// Generate walks the chain until it finds a word unknown to the model whose
+// length is within [minLen, maxLen].
+func (c *MarkovChain) Generate(rng *rand.Rand, minLen, maxLen int) (string, error) {
+	if len(c.transitions) == 0 {
+		return "", fmt.Errorf("markov chain is empty")
+	}
+	for range MARKOV_MAX_ATTEMPTS {
+		state := []rune(strings.Repeat(string(markovBoundary), c.order))
+		word := []rune{}
+		for len(word) <= maxLen {
+			r := c.transitions[string(state)].pick(rng)
+			if r == markovBoundary {
+				break
+			}
+			word = append(word, r)
+			state = append(state[1:], r)
+		}
+		if len(word) < minLen || len(word) > maxLen || c.known[string(word)] {
+			continue
+		}
+		return titleCase(string(word)), nil
+	}
+	return "", fmt.Errorf("failed to synthesize a new word after %d attempts", MARKOV_MAX_ATTEMPTS)
+}
+
+// titleCase capitalizes the first letter of each part of compound names.
+func titleCase(s string) string {
+	upper := true
+	return strings.Map(func(r rune) rune {
+		if upper {
+			upper = false
+			return unicode.ToUpper(r)
+		}
+		if r == ' ' || r == '-' || r == '\'' {
+			upper = true
+		}
+		return r
+	}, s)
+}
+
+type MarkovSettings struct {
+	TableName        string
+	TableFilterKey   string
+	TableFilterValue string
+	Locale           string
+	Order            int
+	MinLength        int
+	MaxLength        int
+}
+
+// ParseMarkov parses 'table:column=value;order=3;min=3;max=12;locale=fr-FR'.
+func ParseMarkov(params ...any) (*MarkovSettings, error) {
+	spec, opts, err := ParseSpecOptions(MarkovOptions, params...)
+	if err != nil {
+		return nil, err
+	}
+	table, filter, ok := strings.Cut(spec, ":")
+	key, value, okFilter := strings.Cut(filter, "=")
+	if !ok || !okFilter {
+		return nil, fmt.Errorf("invalid arguments to MarkovGenerator, expected 'table:column=value' but got '%s'", spec)
+	}
+	ret := &MarkovSettings{
+		TableName:        table,
+		TableFilterKey:   key,
+		TableFilterValue: value,
+		Locale:           opts["locale"],
+		Order:            MARKOV_DEFAULT_ORDER,
+		MinLength:        MARKOV_DEFAULT_MIN_LENGTH,
+		MaxLength:        MARKOV_DEFAULT_MAX_LENGTH,
+	}
+	for k, dst := range map[string]*int{"order": &ret.Order, "min": &ret.MinLength, "max": &ret.MaxLength} {
+		if v, ok := opts[k]; ok {
+			if *dst, err = strconv.Atoi(v); err != nil || *dst < 1 {
+				return nil, fmt.Errorf("invalid markov %s '%s', expected a positive number", k, v)
+			}
+		}
+	}
+	if ret.MinLength > ret.MaxLength {
+		return nil, fmt.Errorf("invalid markov lengths, min %d is greater than max %d", ret.MinLength, ret.MaxLength)
+	}
+	return ret, nil
+}
+
+type MarkovGenerator struct {
+	*CacheGenerator
+
+	db       *sql.DB
+	settings *MarkovSettings
+
+	once     sync.Once
+	trainErr error
+	chain    *MarkovChain
+}
+
+func NewMarkovGenerator(options *generator.GeneratorOptions, db *sql.DB, settings *MarkovSettings) *MarkovGenerator {
+	ret := &MarkovGenerator{
+		db:       db,
+		settings: settings,
+	}
+	ret.CacheGenerator = NewCacheGenerator(options, MARKOV_GENERATOR_NAME, ret.next)
+	return ret
+}
+
+func (g *MarkovGenerator) train() error {
+	s := g.settings
+	rawQuery := fmt.Sprintf("SELECT p.value FROM %s p WHERE p.%s = ? ORDER BY p.id", s.TableName, s.TableFilterKey)
+	params := []any{s.TableFilterValue}
+	if len(s.Locale) > 0 {
+		rawQuery = fmt.Sprintf("SELECT p.value FROM %s p JOIN locale l ON l.id = p.locale_id WHERE p.%s = ? AND l.name = ? ORDER BY p.id", s.TableName, s.TableFilterKey)
+		params = append(params, s.Locale)
+	}
+	rows, err := g.db.Query(rawQuery, params...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	g.chain = NewMarkovChain(s.Order)
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return fmt.Errorf("failed to scan rows: %s", err)
+		}
+		g.chain.Train(value)
+	}
+	if g.chain.Len() == 0 {
+		return fmt.Errorf("invalid markov generator, filter matches nothing: '%s' (params=%v)", rawQuery, params)
+	}
+	return nil
+}
+
+func (g *MarkovGenerator) next() (string, error) {
+	g.once.Do(func() {
+		g.trainErr = g.train()
+	})
+	if g.trainErr != nil {
+		return "", g.trainErr
+	}
+	return g.chain.Generate(g.options.Rand, g.settings.MinLength, g.settings.MaxLength)
+}
@@ -0,0 +1,41 @@
+package generators_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestMarkovChain(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	chain := generators.NewMarkovChain(2)
+	for _, name := range []string{"MARIE", "MARINE", "MARION", "MARTIN", "MARTINE", "CORINNE", "CORENTIN", "CAROLINE", "MARCEL", "MARCELINE"} {
+		chain.Train(name)
+	}
+	for range 20 {
+		v, err := chain.Generate(src, 3, 10)
+		if err != nil {
+			t.Fatalf("failed to generate word, %s", err)
+		}
+		if chain.Contains(v) {
+			t.Errorf("generated word '%s' exists in training set", v)
+		}
+		if n := len([]rune(v)); n < 3 || n > 10 {
+			t.Errorf("generated word '%s' has invalid length %d", v, n)
+		}
+	}
+}
+
+func TestParseMarkov(t *testing.T) {
+	settings, err := generators.ParseMarkov("markov", "person_prop", "type=firstName;order=2;max=8;locale=fr-FR")
+	if err != nil {
+		t.Fatalf("failed to parse markov settings, %s", err)
+	}
+	if settings.TableName != "person_prop" || settings.TableFilterKey != "type" || settings.TableFilterValue != "firstName" {
+		t.Errorf("invalid markov source %+v", settings)
+	}
+	if settings.Order != 2 || settings.MaxLength != 8 || settings.Locale != "fr-FR" {
+		t.Errorf("invalid markov options %+v", settings)
+	}
+}
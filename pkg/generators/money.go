@@ -0,0 +1,186 @@
+package generators
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const MONEY_GENERATOR_NAME = "money"
+
+const MONEY_DEFAULT_LOCALE = "en-US"
+
+// MONEY_BOUNDS_EPSILON is the error tolerated on bounds converted to
+// currency increments.
+const MONEY_BOUNDS_EPSILON = 1e-9
+
+var MoneyOptions = []string{"min", "max", "locale", "format"}
+
+// MoneySuffixLanguages write the currency symbol after the amount, separated
+// by a no-break space.
+var MoneySuffixLanguages = []string{"de", "es", "fr", "it", "pt"}
+
+type MoneyFormat int64
+
+const (
+	MoneyFormatLocale MoneyFormat = iota
+	MoneyFormatRaw
+)
+
+type MoneySettings struct {
+	Currencies []currency.Unit
+	Min        float64
+	Max        float64
+	Locale     language.Tag
+	Format     MoneyFormat
+}
+
+// ParseMoney parses 'EUR|USD;min=0.5;max=1000;locale=fr-FR;format=locale|raw',
+// min and max being in major units.
+func ParseMoney(params ...any) (*MoneySettings, error) {
+	spec, opts, err := ParseSpecOptions(MoneyOptions, params...)
+	if err != nil {
+		return nil, err
+	}
+	ret := &MoneySettings{Min: 0, Max: 1000}
+	if len(spec) == 0 {
+		return nil, fmt.Errorf("invalid money, expected ISO 4217 currency codes such as 'EUR|USD'")
+	}
+	for _, code := range strings.Split(spec, "|") {
+		unit, err := currency.ParseISO(strings.TrimSpace(code))
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency '%s', %s", code, err)
+		}
+		ret.Currencies = append(ret.Currencies, unit)
+	}
+	for k, dst := range map[string]*float64{"min": &ret.Min, "max": &ret.Max} {
+		if v, ok := opts[k]; ok {
+			if *dst, err = strconv.ParseFloat(v, 64); err != nil || math.IsNaN(*dst) || math.IsInf(*dst, 0) {
+				return nil, fmt.Errorf("invalid money %s '%s', expected a number", k, v)
+			}
+		}
+	}
+	if ret.Min > ret.Max {
+		return nil, fmt.Errorf("invalid money bounds, min %v is greater than max %v", ret.Min, ret.Max)
+	}
+	for _, unit := range ret.Currencies {
+		if _, _, err := MinorUnitBounds(unit, ret.Min, ret.Max); err != nil {
+			return nil, err
+		}
+	}
+	locale := opts["locale"]
+	if len(locale) == 0 {
+		locale = MONEY_DEFAULT_LOCALE
+	}
+	if ret.Locale, err = language.Parse(locale); err != nil {
+		return nil, fmt.Errorf("invalid money locale '%s', %s", locale, err)
+	}
+	switch strings.ToLower(opts["format"]) {
+	case "", "locale":
+		ret.Format = MoneyFormatLocale
+	case "raw":
+		ret.Format = MoneyFormatRaw
+	default:
+		return nil, fmt.Errorf("invalid money format '%s', expected 'locale' or 'raw'", opts["format"])
+	}
+	return ret, nil
+}
+
+// MinorUnitBounds returns the bounds of the amounts of unit between lower and
+// upper, in currency increments, failing when none is representable.
+func MinorUnitBounds(unit currency.Unit, lower, upper float64) (int64, int64, error) {
+	scale, inc := currency.Standard.Rounding(unit)
+	factor := math.Pow10(scale) / float64(inc)
+	// tolerate the float error of bounds such as 0.1 * 100 = 10.000000000000002
+	lo := math.Ceil(lower*factor - MONEY_BOUNDS_EPSILON)
+	hi := math.Floor(upper*factor + MONEY_BOUNDS_EPSILON)
+	// float64(math.MaxInt64) is 2^63, the first value out of range
+	limit := float64(math.MaxInt64)
+	if lo*float64(inc) <= -limit || hi*float64(inc) >= limit || hi-lo >= limit {
+		return 0, 0, fmt.Errorf("invalid %s bounds %v..%v, amounts exceed %d minor units", unit, lower, upper, int64(math.MaxInt64))
+	}
+	if hi < lo {
+		return 0, 0, fmt.Errorf("invalid %s bounds %v..%v, no amount is a multiple of %s", unit, lower, upper, FormatMoneyRaw(unit, int64(inc)))
+	}
+	return int64(lo), int64(hi), nil
+}
+
+// RandMinorUnits returns an amount of unit between lower and upper, in minor
+// units (cents for EUR) rounded to the currency increment.
+func RandMinorUnits(rng *rand.Rand, unit currency.Unit, lower, upper float64) (int64, error) {
+	lo, hi, err := MinorUnitBounds(unit, lower, upper)
+	if err != nil {
+		return 0, err
+	}
+	_, inc := currency.Standard.Rounding(unit)
+	return (lo + rng.Int64N(hi-lo+1)) * int64(inc), nil
+}
+
+// FormatMoneyRaw renders minor units with the currency's number of decimals
+// and a dot separator, such as '1234.56'.
+func FormatMoneyRaw(unit currency.Unit, minor int64) string {
+	scale, _ := currency.Standard.Rounding(unit)
+	sign := ""
+	if minor < 0 {
+		sign, minor = "-", -minor
+	}
+	digits := fmt.Sprintf("%0*d", scale+1, minor)
+	if scale == 0 {
+		return sign + digits
+	}
+	return sign + digits[:len(digits)-scale] + "." + digits[len(digits)-scale:]
+}
+
+// FormatMoney renders minor units the way tag writes amounts, such as
+// '1 234,56 €' for fr-FR or '$1,234.56' for en-US.
+func FormatMoney(tag language.Tag, unit currency.Unit, minor int64) string {
+	scale, _ := currency.Standard.Rounding(unit)
+	p := message.NewPrinter(tag)
+	amount := p.Sprint(number.Decimal(float64(minor)/math.Pow10(scale), number.Scale(scale)))
+	symbol := p.Sprint(currency.Symbol(unit))
+	if base, _ := tag.Base(); slices.Contains(MoneySuffixLanguages, base.String()) {
+		return amount + "\u00a0" + symbol
+	}
+	if strings.HasPrefix(amount, "-") {
+		return "-" + symbol + amount[1:]
+	}
+	return symbol + amount
+}
+
+// Rand returns an amount in one of the currencies.
+func (s *MoneySettings) Rand(rng *rand.Rand) (string, error) {
+	unit := s.Currencies[rng.IntN(len(s.Currencies))]
+	minor, err := RandMinorUnits(rng, unit, s.Min, s.Max)
+	if err != nil {
+		return "", err
+	}
+	if s.Format == MoneyFormatRaw {
+		return FormatMoneyRaw(unit, minor), nil
+	}
+	return FormatMoney(s.Locale, unit, minor), nil
+}
+
+type MoneyGenerator struct {
+	*CacheGenerator
+
+	settings *MoneySettings
+}
+
+func NewMoneyGenerator(options *generator.GeneratorOptions, settings *MoneySettings) *MoneyGenerator {
+	return &MoneyGenerator{
+		CacheGenerator: NewCacheGenerator(options, MONEY_GENERATOR_NAME, func() (string, error) {
+			return settings.Rand(options.Rand)
+		}),
+		settings: settings,
+	}
+}
@@ -0,0 +1,62 @@
+package generators_test
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestFormatMoney(t *testing.T) {
+	for _, tc := range []struct {
+		locale   string
+		unit     currency.Unit
+		minor    int64
+		expected string
+	}{
+		{"fr-FR", currency.EUR, 123456, "1\u00a0234,56\u00a0€"},
+		{"en-US", currency.USD, 123456, "$1,234.56"},
+		{"en-US", currency.USD, -5, "-$0.05"},
+		{"es-ES", currency.EUR, 123456, "1.234,56\u00a0€"},
+		{"en-UK", currency.JPY, 1234, "JP¥1,234"},
+	} {
+		if got := generators.FormatMoney(language.MustParse(tc.locale), tc.unit, tc.minor); got != tc.expected {
+			t.Errorf("expected '%s' for %s %s, got '%s'", tc.expected, tc.locale, tc.unit, got)
+		}
+	}
+	for _, tc := range []struct {
+		unit     currency.Unit
+		minor    int64
+		expected string
+	}{
+		{currency.EUR, 123456, "1234.56"},
+		{currency.JPY, 1234, "1234"},
+		{currency.MustParseISO("KWD"), 5, "0.005"},
+		{currency.EUR, -123, "-1.23"},
+	} {
+		if got := generators.FormatMoneyRaw(tc.unit, tc.minor); got != tc.expected {
+			t.Errorf("expected '%s' for %s, got '%s'", tc.expected, tc.unit, got)
+		}
+	}
+}
+
+func TestRandMinorUnits(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	for range 200 {
+		if v, err := generators.RandMinorUnits(src, currency.EUR, 1.5, 2.25); err != nil || v < 150 || v > 225 {
+			t.Fatalf("amount %d out of bounds (%v)", v, err)
+		}
+	}
+	// bounds converted to cents with float errors, such as 0.1 * 100
+	if lo, hi, err := generators.MinorUnitBounds(currency.EUR, 0.1, 1.15); err != nil || lo != 10 || hi != 115 {
+		t.Errorf("expected cents 10..115 but got %d..%d (%v)", lo, hi, err)
+	}
+	for _, tpl := range []string{"", "EURO", "EUR;min=10;max=1", "EUR;format=cents", "EUR;min=0.001;max=0.004", "JPY;min=0.2;max=0.4", "EUR|JPY;min=0.5;max=0.9", "EUR;max=1e20", "EUR;min=-1e20"} {
+		if _, err := generators.ParseMoney("money", tpl); err == nil {
+			t.Errorf("expected an error for money template '%s'", tpl)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package generators
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const NIR_GENERATOR_NAME = "nir"
+const SIREN_GENERATOR_NAME = "siren"
+const SIRET_GENERATOR_NAME = "siret"
+
+var NationalIdOptions = []string{"format"}
+
+// NIRKey computes the key of a 13 characters french NIR, corsican
+// departments 2A and 2B being counted as 19 and 18.
+func NIRKey(nir string) (string, error) {
+	nir = strings.NewReplacer("2A", "19", "2B", "18").Replace(strings.ToUpper(nir))
+	n, err := strconv.ParseInt(nir, 10, 64)
+	if err != nil {
+		return "", fmt.Errorf("invalid NIR '%s', %s", nir, err)
+	}
+	return fmt.Sprintf("%02d", 97-n%97), nil
+}
+
+// RandNIR returns a french social security number (sex, birth year and
+// month, department, commune, order) followed by its key.
+func RandNIR(rng *rand.Rand) (string, error) {
+	dept := fmt.Sprintf("%02d", 1+rng.IntN(95))
+	switch dept {
+	case "20":
+		dept = []string{"2A", "2B"}[rng.IntN(2)]
+	}
+	nir := fmt.Sprintf("%d%02d%02d%s%03d%03d", 1+rng.IntN(2), rng.IntN(100), 1+rng.IntN(12), dept, 1+rng.IntN(990), 1+rng.IntN(999))
+	key, err := NIRKey(nir)
+	if err != nil {
+		return "", err
+	}
+	return nir + key, nil
+}
+
+// formatNIR groups a NIR as printed on the 'carte vitale'.
+func formatNIR(nir string) string {
+	return strings.Join([]string{nir[0:1], nir[1:3], nir[3:5], nir[5:7], nir[7:10], nir[10:13], nir[13:15]}, " ")
+}
+
+// RandSIREN returns a 9 digits french company identifier.
+func RandSIREN(rng *rand.Rand) string {
+	digits := randDigits(rng, 8)
+	return digits + strconv.Itoa(LuhnCheckDigit(digits))
+}
+
+// RandSIRET returns a 14 digits french establishment identifier: a SIREN
+// followed by a 5 digits establishment number, the whole being Luhn-valid.
+func RandSIRET(rng *rand.Rand) string {
+	digits := RandSIREN(rng) + randDigits(rng, 4)
+	return digits + strconv.Itoa(LuhnCheckDigit(digits))
+}
+
+type NationalIdGenerator struct {
+	*CacheGenerator
+}
+
+func NewNationalIdGenerator(options *generator.GeneratorOptions, name string, grouped bool) (*NationalIdGenerator, error) {
+	var next CacheGenFunc
+	switch name {
+	case NIR_GENERATOR_NAME:
+		next = func() (string, error) {
+			nir, err := RandNIR(options.Rand)
+			if err != nil || !grouped {
+				return nir, err
+			}
+			return formatNIR(nir), nil
+		}
+	case SIREN_GENERATOR_NAME:
+		next = func() (string, error) {
+			siren := RandSIREN(options.Rand)
+			if !grouped {
+				return siren, nil
+			}
+			return groupBy(siren, 3), nil
+		}
+	case SIRET_GENERATOR_NAME:
+		next = func() (string, error) {
+			siret := RandSIRET(options.Rand)
+			if !grouped {
+				return siret, nil
+			}
+			return fmt.Sprintf("%s %s", groupBy(siret[:9], 3), siret[9:]), nil
+		}
+	default:
+		return nil, fmt.Errorf("unknown national identifier '%s'", name)
+	}
+	return &NationalIdGenerator{
+		CacheGenerator: NewCacheGenerator(options, name, next),
+	}, nil
+}
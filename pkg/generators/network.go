@@ -0,0 +1,387 @@
+package generators
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/rand/v2"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const IPV4_GENERATOR_NAME = "ipv4"
+const IPV6_GENERATOR_NAME = "ipv6"
+const MAC_GENERATOR_NAME = "mac"
+const HOSTNAME_GENERATOR_NAME = "hostname"
+const URL_GENERATOR_NAME = "url"
+
+const IPV4_DEFAULT_PREFIX = "0.0.0.0/0"
+
+// IPV6_DEFAULT_PREFIX is the documentation prefix of RFC 3849.
+const IPV6_DEFAULT_PREFIX = "2001:db8::/32"
+
+const HOSTNAME_MAX_LABEL_LEN = 63
+const HOSTNAME_MAX_LEN = 253
+const HOSTNAME_MAX_RAND_LABEL_LEN = 12
+
+var DefaultTLDs = []string{"com", "net", "org", "io", "dev", "fr", "es", "co.uk"}
+
+var MACOptions = []string{"format"}
+var HostnameOptions = []string{"min", "max"}
+var URLOptions = []string{"tld", "path", "query"}
+
+// RandPrefixAddr returns an address of prefix whose host bits are random.
+// Unless the prefix is a /31 or /32 (resp. /127, /128), the all-zeros host
+// is excluded and so is, for IPv4, the broadcast address.
+func RandPrefixAddr(rng *rand.Rand, prefix netip.Prefix) netip.Addr {
+	prefix = prefix.Masked()
+	bytes := prefix.Addr().AsSlice()
+	bits := len(bytes) * 8
+	hi, lo := binary.BigEndian.Uint64(pad16(bytes)[:8]), binary.BigEndian.Uint64(pad16(bytes)[8:])
+	hostBits := bits - prefix.Bits()
+	for {
+		rhi, rlo := rng.Uint64(), rng.Uint64()
+		mhi, mlo := hostMask(hostBits)
+		h, l := hi|(rhi&mhi), lo|(rlo&mlo)
+		if hostBits > 1 {
+			if h&mhi == 0 && l&mlo == 0 {
+				continue
+			}
+			if bits == 32 && h&mhi == mhi && l&mlo == mlo {
+				continue
+			}
+		}
+		buf := make([]byte, 16)
+		binary.BigEndian.PutUint64(buf[:8], h)
+		binary.BigEndian.PutUint64(buf[8:], l)
+		addr, _ := netip.AddrFromSlice(buf[16-len(bytes):])
+		return addr
+	}
+}
+
+// pad16 left-pads an IPv4 address to 16 bytes.
+func pad16(b []byte) []byte {
+	return append(make([]byte, 16-len(b)), b...)
+}
+
+// hostMask returns the 128 bit mask of the n lowest bits.
+func hostMask(n int) (uint64, uint64) {
+	switch {
+	case n <= 0:
+		return 0, 0
+	case n < 64:
+		return 0, 1<<n - 1
+	case n == 64:
+		return 0, ^uint64(0)
+	case n < 128:
+		return 1<<(n-64) - 1, ^uint64(0)
+	}
+	return ^uint64(0), ^uint64(0)
+}
+
+// ParsePrefix parses a CIDR of the expected IP version, def being used when
+// no template is given.
+func ParsePrefix(version int, def string, params ...any) (netip.Prefix, error) {
+	spec, _, err := ParseSpecOptions(nil, params...)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	if len(spec) == 0 {
+		spec = def
+	}
+	prefix, err := netip.ParsePrefix(spec)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IPv%d prefix '%s', %s", version, spec, err)
+	}
+	if (version == 4) != prefix.Addr().Is4() {
+		return netip.Prefix{}, fmt.Errorf("invalid IPv%d prefix '%s', wrong address family", version, spec)
+	}
+	return prefix, nil
+}
+
+type IPGenerator struct {
+	*CacheGenerator
+
+	prefix netip.Prefix
+}
+
+func NewIPGenerator(options *generator.GeneratorOptions, name string, prefix netip.Prefix) *IPGenerator {
+	return &IPGenerator{
+		CacheGenerator: NewCacheGenerator(options, name, func() (string, error) {
+			return RandPrefixAddr(options.Rand, prefix).String(), nil
+		}),
+		prefix: prefix,
+	}
+}
+
+type MACFormat int64
+
+const (
+	MACFormatColon MACFormat = iota
+	MACFormatDash
+	MACFormatDot
+)
+
+// ParseMAC parses 'OUI;format=colon|dash|dot' where the optional OUI is made
+// of 3 bytes, such as '00:1A:2B'.
+func ParseMAC(params ...any) (oui []byte, format MACFormat, err error) {
+	spec, opts, err := ParseSpecOptions(MACOptions, params...)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(spec) > 0 {
+		oui, err = hex.DecodeString(strings.NewReplacer(":", "", "-", "", ".", "").Replace(spec))
+		if err != nil || len(oui) != 3 {
+			return nil, 0, fmt.Errorf("invalid OUI '%s', expected 3 hexadecimal bytes", spec)
+		}
+	}
+	switch strings.ToLower(opts["format"]) {
+	case "", "colon":
+		format = MACFormatColon
+	case "dash":
+		format = MACFormatDash
+	case "dot":
+		format = MACFormatDot
+	default:
+		return nil, 0, fmt.Errorf("invalid MAC format '%s', expected one of colon, dash or dot", opts["format"])
+	}
+	return oui, format, nil
+}
+
+// RandMAC returns a MAC address starting with oui, or a locally administered
+// unicast address when oui is empty.
+func RandMAC(rng *rand.Rand, oui []byte) []byte {
+	mac := make([]byte, 6)
+	for i := range mac {
+		mac[i] = byte(rng.IntN(256))
+	}
+	if len(oui) == 3 {
+		copy(mac, oui)
+	} else {
+		mac[0] = mac[0]&0xfc | 0x02
+	}
+	return mac
+}
+
+func FormatMAC(mac []byte, format MACFormat) string {
+	s := hex.EncodeToString(mac)
+	switch format {
+	case MACFormatDot:
+		return s[0:4] + "." + s[4:8] + "." + s[8:12]
+	case MACFormatDash:
+		return strings.ToUpper(strings.Join(splitEvery(s, 2), "-"))
+	}
+	return strings.Join(splitEvery(s, 2), ":")
+}
+
+type MACGenerator struct {
+	*CacheGenerator
+
+	oui []byte
+}
+
+func NewMACGenerator(options *generator.GeneratorOptions, oui []byte, format MACFormat) *MACGenerator {
+	return &MACGenerator{
+		CacheGenerator: NewCacheGenerator(options, MAC_GENERATOR_NAME, func() (string, error) {
+			return FormatMAC(RandMAC(options.Rand, oui), format), nil
+		}),
+		oui: oui,
+	}
+}
+
+// randLabel returns an RFC 1123 label of n characters: lower case letters and
+// digits, with hyphens only inside.
+func randLabel(rng *rand.Rand, n int) string {
+	const chars = "abcdefghijklmnopqrstuvwxyz0123456789"
+	buf := make([]byte, n)
+	for i := range buf {
+		if i > 0 && i < n-1 && buf[i-1] != '-' && rng.IntN(10) == 0 {
+			buf[i] = '-'
+		} else if i == 0 {
+			buf[i] = chars[rng.IntN(26)]
+		} else {
+			buf[i] = chars[rng.IntN(len(chars))]
+		}
+	}
+	return string(buf)
+}
+
+// ParseTLDs parses 'com|net|fr', an empty list meaning DefaultTLDs.
+func ParseTLDs(spec string) ([]string, error) {
+	if len(spec) == 0 {
+		return DefaultTLDs, nil
+	}
+	tlds := []string{}
+	for _, tld := range strings.Split(spec, "|") {
+		tld = strings.ToLower(strings.Trim(strings.TrimSpace(tld), "."))
+		for _, label := range strings.Split(tld, ".") {
+			if !ValidHostnameLabel(label) {
+				return nil, fmt.Errorf("invalid top-level domain '%s'", tld)
+			}
+		}
+		tlds = append(tlds, tld)
+	}
+	return tlds, nil
+}
+
+// ValidHostnameLabel reports whether label complies with RFC 1123.
+func ValidHostnameLabel(label string) bool {
+	if len(label) == 0 || len(label) > HOSTNAME_MAX_LABEL_LEN || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, r := range label {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+// ValidHostname reports whether every label of host complies with RFC 1123
+// and the whole name fits in 253 characters.
+func ValidHostname(host string) bool {
+	if len(host) == 0 || len(host) > HOSTNAME_MAX_LEN {
+		return false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if !ValidHostnameLabel(label) {
+			return false
+		}
+	}
+	return true
+}
+
+type HostnameSettings struct {
+	TLDs []string
+	Min  int
+	Max  int
+}
+
+// ParseHostname parses 'com|net;min=N;max=N', min and max bounding the
+// number of labels before the top-level domain.
+func ParseHostname(params ...any) (*HostnameSettings, error) {
+	spec, opts, err := ParseSpecOptions(HostnameOptions, params...)
+	if err != nil {
+		return nil, err
+	}
+	ret := &HostnameSettings{Min: 1, Max: 2}
+	if ret.TLDs, err = ParseTLDs(spec); err != nil {
+		return nil, err
+	}
+	for k, dst := range map[string]*int{"min": &ret.Min, "max": &ret.Max} {
+		if v, ok := opts[k]; ok {
+			if *dst, err = strconv.Atoi(v); err != nil || *dst < 1 {
+				return nil, fmt.Errorf("invalid hostname %s '%s', expected a positive number", k, v)
+			}
+		}
+	}
+	if ret.Min > ret.Max {
+		return nil, fmt.Errorf("invalid hostname bounds, min %d is greater than max %d", ret.Min, ret.Max)
+	}
+	if longest := slices.MaxFunc(ret.TLDs, func(a, b string) int { return len(a) - len(b) }); ret.Max*(HOSTNAME_MAX_RAND_LABEL_LEN+1)+len(longest) > HOSTNAME_MAX_LEN {
+		return nil, fmt.Errorf("invalid hostname max %d, names could exceed %d characters", ret.Max, HOSTNAME_MAX_LEN)
+	}
+	return ret, nil
+}
+
+// Rand returns a hostname such as 'mail.x3-kq.example.com'.
+func (s *HostnameSettings) Rand(rng *rand.Rand) string {
+	labels := []string{}
+	for range s.Min + rng.IntN(s.Max-s.Min+1) {
+		labels = append(labels, randLabel(rng, 3+rng.IntN(HOSTNAME_MAX_RAND_LABEL_LEN-2)))
+	}
+	return strings.Join(append(labels, s.TLDs[rng.IntN(len(s.TLDs))]), ".")
+}
+
+type HostnameGenerator struct {
+	*CacheGenerator
+
+	settings *HostnameSettings
+}
+
+func NewHostnameGenerator(options *generator.GeneratorOptions, settings *HostnameSettings) *HostnameGenerator {
+	return &HostnameGenerator{
+		CacheGenerator: NewCacheGenerator(options, HOSTNAME_GENERATOR_NAME, func() (string, error) {
+			return settings.Rand(options.Rand), nil
+		}),
+		settings: settings,
+	}
+}
+
+type URLSettings struct {
+	Schemes  []string
+	Host     *HostnameSettings
+	MaxPath  int
+	MaxQuery int
+}
+
+// ParseURL parses 'https|http;tld=com|fr;path=N;query=N', path and query
+// being the maximum number of path segments and query parameters.
+func ParseURL(params ...any) (*URLSettings, error) {
+	spec, opts, err := ParseSpecOptions(URLOptions, params...)
+	if err != nil {
+		return nil, err
+	}
+	ret := &URLSettings{Schemes: []string{"https"}, Host: &HostnameSettings{Min: 1, Max: 2}, MaxPath: 3, MaxQuery: 2}
+	if len(spec) > 0 {
+		ret.Schemes = []string{}
+		for _, scheme := range strings.Split(spec, "|") {
+			scheme = strings.ToLower(strings.TrimSpace(scheme))
+			if _, err := url.Parse(scheme + "://host"); err != nil || len(scheme) == 0 {
+				return nil, fmt.Errorf("invalid URL scheme '%s'", scheme)
+			}
+			ret.Schemes = append(ret.Schemes, scheme)
+		}
+	}
+	if ret.Host.TLDs, err = ParseTLDs(opts["tld"]); err != nil {
+		return nil, err
+	}
+	for k, dst := range map[string]*int{"path": &ret.MaxPath, "query": &ret.MaxQuery} {
+		if v, ok := opts[k]; ok {
+			if *dst, err = strconv.Atoi(v); err != nil || *dst < 0 {
+				return nil, fmt.Errorf("invalid URL %s '%s', expected a number", k, v)
+			}
+		}
+	}
+	return ret, nil
+}
+
+// Rand returns a URL with a random host, path and query string.
+func (s *URLSettings) Rand(rng *rand.Rand) string {
+	u := url.URL{
+		Scheme: s.Schemes[rng.IntN(len(s.Schemes))],
+		Host:   s.Host.Rand(rng),
+		Path:   "/",
+	}
+	segments := []string{}
+	for range rng.IntN(s.MaxPath + 1) {
+		segments = append(segments, randLabel(rng, 2+rng.IntN(10)))
+	}
+	u.Path += strings.Join(segments, "/")
+	query := url.Values{}
+	for range rng.IntN(s.MaxQuery + 1) {
+		query.Add(randLabel(rng, 1+rng.IntN(6)), randLabel(rng, 1+rng.IntN(10)))
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+type URLGenerator struct {
+	*CacheGenerator
+
+	settings *URLSettings
+}
+
+func NewURLGenerator(options *generator.GeneratorOptions, settings *URLSettings) *URLGenerator {
+	return &URLGenerator{
+		CacheGenerator: NewCacheGenerator(options, URL_GENERATOR_NAME, func() (string, error) {
+			return settings.Rand(options.Rand), nil
+		}),
+		settings: settings,
+	}
+}
@@ -0,0 +1,82 @@
+package generators_test
+
+import (
+	"math/rand/v2"
+	"net/netip"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestRandPrefixAddr(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	for spec, excluded := range map[string][]string{
+		"192.168.1.0/30": {"192.168.1.0", "192.168.1.3"},
+		"10.0.0.0/8":     {"10.0.0.0", "10.255.255.255"},
+		"2001:db8::/126": {"2001:db8::"},
+		"192.168.1.4/32": {},
+	} {
+		prefix := netip.MustParsePrefix(spec)
+		for range 200 {
+			addr := generators.RandPrefixAddr(src, prefix)
+			if !prefix.Contains(addr) {
+				t.Fatalf("address %s is outside of %s", addr, spec)
+			}
+			for _, ex := range excluded {
+				if addr.String() == ex {
+					t.Fatalf("address %s of %s should have been excluded", addr, spec)
+				}
+			}
+		}
+	}
+	if _, err := generators.ParsePrefix(4, generators.IPV4_DEFAULT_PREFIX, "ipv4", "2001:db8::/32"); err == nil {
+		t.Errorf("expected an error for an IPv6 prefix in an IPv4 generator")
+	}
+}
+
+func TestMAC(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	oui, format, err := generators.ParseMAC("mac", "00-50-56;format=dash")
+	if err != nil {
+		t.Fatalf("failed to parse MAC settings, %s", err)
+	}
+	if mac := generators.FormatMAC(generators.RandMAC(src, oui), format); !strings.HasPrefix(mac, "00-50-56-") || len(mac) != 17 {
+		t.Errorf("invalid MAC address '%s'", mac)
+	}
+	if mac := generators.RandMAC(src, nil); mac[0]&0x03 != 0x02 {
+		t.Errorf("expected a locally administered unicast address, got %x", mac)
+	}
+	if got := generators.FormatMAC([]byte{0, 0x1a, 0x2b, 0x3c, 0x4d, 0x5e}, generators.MACFormatDot); got != "001a.2b3c.4d5e" {
+		t.Errorf("invalid dotted MAC address '%s'", got)
+	}
+}
+
+func TestHostnameAndURL(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	host, err := generators.ParseHostname("hostname", "com|co.uk;min=2;max=4")
+	if err != nil {
+		t.Fatalf("failed to parse hostname settings, %s", err)
+	}
+	for range 200 {
+		if h := host.Rand(src); !generators.ValidHostname(h) {
+			t.Fatalf("invalid hostname '%s'", h)
+		}
+	}
+	settings, err := generators.ParseURL("url", "http|https;tld=fr;path=4;query=3")
+	if err != nil {
+		t.Fatalf("failed to parse URL settings, %s", err)
+	}
+	for range 200 {
+		u, err := url.Parse(settings.Rand(src))
+		if err != nil || !strings.HasSuffix(u.Hostname(), ".fr") || !generators.ValidHostname(u.Hostname()) {
+			t.Fatalf("invalid URL '%v', %v", u, err)
+		}
+	}
+	for _, tpl := range []string{"com;max=40", "-com", "com;min=3;max=2"} {
+		if _, err := generators.ParseHostname("hostname", tpl); err == nil {
+			t.Errorf("expected an error for hostname template '%s'", tpl)
+		}
+	}
+}
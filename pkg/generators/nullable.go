@@ -0,0 +1,82 @@
+package generators
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const NULLABLE_GENERATOR_NAME = "nullable"
+
+const NULLABLE_DEFAULT_RATIO = 0.1
+
+var NullableOptions = []string{"ratio"}
+
+// ParseRatio parses a probability between 0 and 1, def being used when empty.
+func ParseRatio(v string, def float64) (float64, error) {
+	if len(v) == 0 {
+		return def, nil
+	}
+	ratio, err := strconv.ParseFloat(v, 64)
+	if err != nil || ratio < 0 || ratio > 1 {
+		return 0, fmt.Errorf("invalid ratio '%s', expected a number between 0 and 1", v)
+	}
+	return ratio, nil
+}
+
+// ParseNullable parses 'resource.name;ratio=0.2', ratio being the fraction
+// of null values.
+func ParseNullable(params ...any) (resource string, ratio float64, err error) {
+	spec, opts, err := ParseSpecOptions(NullableOptions, params...)
+	if err != nil {
+		return "", 0, err
+	}
+	if len(spec) == 0 {
+		return "", 0, fmt.Errorf("invalid nullable, expected a resource name")
+	}
+	if ratio, err = ParseRatio(opts["ratio"], NULLABLE_DEFAULT_RATIO); err != nil {
+		return "", 0, err
+	}
+	return spec, ratio, nil
+}
+
+// NullableGenerator yields generator.NULL_VALUE for a fraction of calls, and
+// a value of the wrapped resource otherwise.
+type NullableGenerator struct {
+	*CacheGenerator
+
+	resource  string
+	ratio     float64
+	resGetter func(name string) generator.Generator
+}
+
+func NewNullableGenerator(options *generator.GeneratorOptions, resource string, ratio float64, resGetter func(name string) generator.Generator) *NullableGenerator {
+	ret := &NullableGenerator{
+		resource:  resource,
+		ratio:     ratio,
+		resGetter: resGetter,
+	}
+	ret.CacheGenerator = NewCacheGenerator(options, NULLABLE_GENERATOR_NAME, func() (string, error) {
+		if options.Rand.Float64() < ret.ratio {
+			return generator.NULL_VALUE, nil
+		}
+		gen := ret.resGetter(ret.resource)
+		if gen == nil {
+			return "", fmt.Errorf("unknown nullable resource '%s'", ret.resource)
+		}
+		return SampleValue(gen)
+	})
+	return ret
+}
+
+func (g *NullableGenerator) Validate() error {
+	if g.resGetter(g.resource) == nil {
+		return fmt.Errorf("unknown nullable resource '%s'", g.resource)
+	}
+	return CheckReferenceCycles(g.References(), g.resGetter)
+}
+
+func (g *NullableGenerator) References() []string {
+	return []string{g.resource}
+}
@@ -0,0 +1,63 @@
+package generators_test
+
+import (
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestNullableGenerator(t *testing.T) {
+	options := generator.NewGeneratorOptions()
+	options.OnlyUniqueValues = true
+	inner := generators.NewSequenceGenerator(options, mustParseSequence(t, "%d"))
+	getter := func(name string) generator.Generator {
+		if name == "id" {
+			return inner
+		}
+		return nil
+	}
+	g := generators.NewNullableGenerator(options, "id", 0.5, getter)
+	if err := g.Validate(); err != nil {
+		t.Fatalf("unexpected validation error, %s", err)
+	}
+	nulls := 0
+	for range 1000 {
+		value, err := g.Next()
+		if err != nil {
+			t.Fatalf("failed to generate value, %s", err)
+		}
+		if generator.IsNull(value) {
+			nulls++
+		}
+	}
+	if nulls < 400 || nulls > 600 {
+		t.Errorf("expected about 500 nulls, got %d", nulls)
+	}
+	if err := generators.NewNullableGenerator(options, "unknown", 0.5, getter).Validate(); err == nil {
+		t.Errorf("expected an error for an unknown resource")
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	values, ratio, err := generators.ParseBool("bool", "yes|no;ratio=0.8")
+	if err != nil {
+		t.Fatalf("failed to parse bool settings, %s", err)
+	}
+	if values != [2]string{"yes", "no"} || ratio != 0.8 {
+		t.Errorf("invalid bool settings %v %v", values, ratio)
+	}
+	for _, tpl := range []string{"yes", ";ratio=2", ";ratio=abc"} {
+		if _, _, err := generators.ParseBool("bool", tpl); err == nil {
+			t.Errorf("expected an error for bool template '%s'", tpl)
+		}
+	}
+}
+
+func mustParseSequence(t *testing.T, tpl string) *generators.Sequence {
+	seq, err := generators.ParseSequence("sequence", tpl)
+	if err != nil {
+		t.Fatalf("failed to parse sequence '%s', %s", tpl, err)
+	}
+	return seq
+}
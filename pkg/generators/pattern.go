@@ -2,40 +2,236 @@ package generators
 
 import (
 	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/welschmorgan/datagen/pkg/generator"
 )
 
+// PATTERN_GENERATOR_NAME parses templates with the legacy grammar, see
+// ParseLegacyPatternParts, and PATTERN_V2_GENERATOR_NAME with the placeholder
+// grammar, see ParsePatternParts. Both live side by side so that templates
+// stored by older versions keep their meaning.
 const PATTERN_GENERATOR_NAME = "pattern"
+const PATTERN_V2_GENERATOR_NAME = "pattern_v2"
+
+// PatternClasses lists the character classes usable as '{class:count}'.
+var PatternClasses = map[string]string{
+	"digit": "0123456789",
+	"hex":   "0123456789abcdef",
+	"HEX":   "0123456789ABCDEF",
+	"lower": "abcdefghijklmnopqrstuvwxyz",
+	"upper": "ABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"alpha": "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ",
+	"alnum": "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ",
+}
+
+// PatternPart is a piece of a pattern, either literal or randomized.
+type PatternPart interface {
+	Rand(rng *rand.Rand) string
+}
+
+type PatternLiteral string
+
+func (p PatternLiteral) Rand(rng *rand.Rand) string {
+	return string(p)
+}
+
+type PatternRangePart struct {
+	Range[int64]
+}
+
+func (p *PatternRangePart) Rand(rng *rand.Rand) string {
+	return p.RandPadded(rng)
+}
+
+type PatternChoice []string
+
+func (p PatternChoice) Rand(rng *rand.Rand) string {
+	return p[rng.IntN(len(p))]
+}
+
+type PatternChars struct {
+	chars []rune
+	count int
+}
+
+func (p *PatternChars) Rand(rng *rand.Rand) string {
+	buf := strings.Builder{}
+	for range p.count {
+		buf.WriteRune(p.chars[rng.IntN(len(p.chars))])
+	}
+	return buf.String()
+}
 
 type PatternGenerator struct {
 	*CacheGenerator
 
 	pattern string
+	parts   []PatternPart
 }
 
-func NewPatternGenerator(options *generator.GeneratorOptions, pattern string) *PatternGenerator {
-	matchId := 0
-	ranges := map[string]Range[int64]{}
-	evaledPattern := PatternRange.ReplaceAllStringFunc(pattern, func(match string) string {
-		matchId += 1
-		key := fmt.Sprintf("{group_%d}", matchId)
-		range_, err := ParseRange(match)
-		if err != nil {
-			panic(err)
-		}
-		ranges[key] = range_
-		return key
-	})
+func NewPatternGenerator(options *generator.GeneratorOptions, name, pattern string, parts []PatternPart) *PatternGenerator {
 	return &PatternGenerator{
-		CacheGenerator: NewCacheGenerator(options, PATTERN_GENERATOR_NAME, func() (string, error) {
-			pattern := evaledPattern
-			for k, v := range ranges {
-				pattern = strings.ReplaceAll(pattern, k, v.RandPadded())
+		CacheGenerator: NewCacheGenerator(options, name, func() (string, error) {
+			buf := strings.Builder{}
+			for _, part := range parts {
+				buf.WriteString(part.Rand(options.Rand))
 			}
-			return pattern, nil
+			return buf.String(), nil
 		}),
 		pattern: pattern,
+		parts:   parts,
+	}
+}
+
+// ParseLegacyPatternParts parses the original grammar, where every digit run
+// is a range or a list of discrete values ('+33 6|7 00..99').
+func ParseLegacyPatternParts(pattern string) ([]PatternPart, error) {
+	parts := []PatternPart{}
+	last := 0
+	for _, loc := range PatternRange.FindAllStringIndex(pattern, -1) {
+		if loc[0] > last {
+			parts = append(parts, PatternLiteral(pattern[last:loc[0]]))
+		}
+		range_, err := ParseRange(pattern[loc[0]:loc[1]])
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern '%s', %s", pattern, err)
+		}
+		parts = append(parts, &PatternRangePart{Range: range_})
+		last = loc[1]
+	}
+	if last < len(pattern) {
+		parts = append(parts, PatternLiteral(pattern[last:]))
+	}
+	return parts, nil
+}
+
+// ParsePatternParts parses patterns with explicit placeholders, everything
+// outside braces is literal and '\' escapes the next character:
+//
+//	{00..99}   inclusive padded integer range, with optional exclusions ('{1..9!6|7}')
+//	{A..Z}     inclusive character range, with optional exclusions ('{A..Z!I|O}')
+//	{hex:8}    characters from a class of PatternClasses, repeated
+//	{1|6|7}    one of the listed values
+func ParsePatternParts(pattern string) ([]PatternPart, error) {
+	parts := []PatternPart{}
+	accu := strings.Builder{}
+	inPlaceholder := false
+	escaped := false
+	for pos, ch := range pattern {
+		switch {
+		case escaped:
+			accu.WriteRune(ch)
+			escaped = false
+		case ch == '\\':
+			escaped = true
+		case ch == '{':
+			if inPlaceholder {
+				return nil, fmt.Errorf("invalid pattern '%s', nested '{' at offset %d", pattern, pos)
+			}
+			if accu.Len() > 0 {
+				parts = append(parts, PatternLiteral(accu.String()))
+			}
+			accu.Reset()
+			inPlaceholder = true
+		case ch == '}':
+			if !inPlaceholder {
+				return nil, fmt.Errorf("invalid pattern '%s', unexpected '}' at offset %d", pattern, pos)
+			}
+			part, err := parsePlaceholder(accu.String())
+			if err != nil {
+				return nil, fmt.Errorf("invalid pattern '%s', %s", pattern, err)
+			}
+			parts = append(parts, part)
+			accu.Reset()
+			inPlaceholder = false
+		default:
+			accu.WriteRune(ch)
+		}
+	}
+	if escaped {
+		return nil, fmt.Errorf("invalid pattern '%s', dangling escape character", pattern)
+	}
+	if inPlaceholder {
+		return nil, fmt.Errorf("invalid pattern '%s', unterminated placeholder", pattern)
+	}
+	if accu.Len() > 0 {
+		parts = append(parts, PatternLiteral(accu.String()))
+	}
+	return parts, nil
+}
+
+func parsePlaceholder(s string) (PatternPart, error) {
+	s = strings.TrimSpace(s)
+	if len(s) == 0 {
+		return nil, fmt.Errorf("empty placeholder")
+	}
+	if name, count, ok := strings.Cut(s, ":"); ok || PatternClasses[s] != "" {
+		chars, found := PatternClasses[name]
+		if !found {
+			return nil, fmt.Errorf("unknown character class '%s'", name)
+		}
+		n := 1
+		if ok {
+			var err error
+			if n, err = strconv.Atoi(count); err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid character count '%s' for class '%s'", count, name)
+			}
+		}
+		return &PatternChars{chars: []rune(chars), count: n}, nil
+	}
+	if bounds := strings.SplitN(s, "..", 2); len(bounds) == 2 && utf8.RuneCountInString(bounds[0]) == 1 && !isDigits(bounds[0]) {
+		return parseCharRange(bounds[0], bounds[1])
+	}
+	if strings.Contains(s, "..") {
+		range_, err := ParseRange(s)
+		if err != nil {
+			return nil, err
+		}
+		if r, ok := range_.(*IntRange); ok {
+			r.inclusive = true
+		}
+		return &PatternRangePart{Range: range_}, nil
+	}
+	return PatternChoice(strings.Split(s, "|")), nil
+}
+
+func parseCharRange(lo, rest string) (PatternPart, error) {
+	hi, exclusions, _ := strings.Cut(rest, "!")
+	if utf8.RuneCountInString(hi) != 1 {
+		return nil, fmt.Errorf("invalid character range '%s..%s'", lo, rest)
+	}
+	from, to := []rune(lo)[0], []rune(hi)[0]
+	if from > to {
+		return nil, fmt.Errorf("invalid character range '%s..%s', bounds are reversed", lo, hi)
+	}
+	excluded := []rune{}
+	if len(exclusions) > 0 {
+		for _, x := range strings.Split(exclusions, "|") {
+			excluded = append(excluded, []rune(x)...)
+		}
+	}
+	chars := []rune{}
+	for r := from; r <= to; r++ {
+		if !slices.Contains(excluded, r) {
+			chars = append(chars, r)
+		}
+	}
+	if len(chars) == 0 {
+		return nil, fmt.Errorf("invalid character range '%s..%s', every character is excluded", lo, rest)
+	}
+	return &PatternChars{chars: chars, count: 1}, nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
 	}
+	return len(s) > 0
 }
@@ -0,0 +1,114 @@
+package generators_test
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestPatternGenerator(t *testing.T) {
+	patterns := map[string]*regexp.Regexp{
+		`+33 {6|7} {00..99}`:        regexp.MustCompile(`^\+33 [67] \d\d$`),
+		`{A..Z!I|O}{a..c}-{hex:8}`:  regexp.MustCompile(`^[A-HJ-NP-Z][a-c]-[0-9a-f]{8}$`),
+		`\{{digit:3}\} 42 {1..9!5}`: regexp.MustCompile(`^\{\d{3}\} 42 [1-46-9]$`),
+	}
+	for pattern, check := range patterns {
+		parts, err := generators.ParsePatternParts(pattern)
+		if err != nil {
+			t.Fatalf("failed to parse pattern '%s', %s", pattern, err)
+		}
+		g := generators.NewPatternGenerator(generator.NewGeneratorOptions(), generators.PATTERN_V2_GENERATOR_NAME, pattern, parts)
+		for range 100 {
+			v, _ := g.Next()
+			if !check.MatchString(v) {
+				t.Errorf("value '%s' does not match pattern '%s'", v, pattern)
+			}
+		}
+	}
+}
+
+func TestPatternRangesAreInclusive(t *testing.T) {
+	digits := func(min, max int, format string, exclude ...int) []string {
+		ret := []string{}
+		for i := min; i <= max; i++ {
+			if !slices.Contains(exclude, i) {
+				ret = append(ret, fmt.Sprintf(format, i))
+			}
+		}
+		return ret
+	}
+	patterns := map[string][]string{
+		"{0..9}":     digits(0, 9, "%d"),
+		"{1..9!5}":   digits(1, 9, "%d", 5),
+		"{00..99}":   digits(0, 99, "%02d"),
+		"{A..Z!I|O}": strings.Split("ABCDEFGHJKLMNPQRSTUVWXYZ", ""),
+	}
+	options := generator.NewGeneratorOptions()
+	options.Rand = rand.New(rand.NewPCG(1, 2))
+	for pattern, expected := range patterns {
+		parts, err := generators.ParsePatternParts(pattern)
+		if err != nil {
+			t.Fatalf("failed to parse pattern '%s', %s", pattern, err)
+		}
+		g := generators.NewPatternGenerator(options, generators.PATTERN_V2_GENERATOR_NAME, pattern, parts)
+		seen := map[string]bool{}
+		for range 3000 {
+			v, _ := g.Next()
+			seen[v] = true
+		}
+		if got := slices.Sorted(maps.Keys(seen)); !slices.Equal(got, expected) {
+			t.Errorf("expected pattern '%s' to yield %v but got %v", pattern, expected, got)
+		}
+	}
+}
+
+func TestParseLegacyPatternParts(t *testing.T) {
+	pattern := "+33 6|7 00..99"
+	parts, err := generators.ParseLegacyPatternParts(pattern)
+	if err != nil {
+		t.Fatalf("failed to parse pattern '%s', %s", pattern, err)
+	}
+	g := generators.NewPatternGenerator(generator.NewGeneratorOptions(), generators.PATTERN_GENERATOR_NAME, pattern, parts)
+	check := regexp.MustCompile(`^\+\d+ [67] \d\d$`)
+	for range 100 {
+		v, _ := g.Next()
+		if !check.MatchString(v) {
+			t.Errorf("value '%s' does not match legacy pattern '%s'", v, pattern)
+		}
+	}
+}
+
+func TestParsePatternPartsErrors(t *testing.T) {
+	for _, pattern := range []string{"{unterminated", "stray}", "{}", "{nope:3}", "{hex:0}", "{Z..A}", `trailing\`} {
+		if _, err := generators.ParsePatternParts(pattern); err == nil {
+			t.Errorf("expected an error for pattern '%s'", pattern)
+		}
+	}
+}
+
+func TestPatternGrammars(t *testing.T) {
+	options := generator.NewGeneratorOptions()
+	legacy, err := generators.AllocateGeneratorPattern(options, "pattern", "+33 6|7 00..99")
+	if err != nil {
+		t.Fatalf("failed to allocate legacy pattern, %s", err)
+	}
+	v2, err := generators.AllocateGeneratorPatternV2(options, "pattern_v2", "+33 {6|7} {00..99}")
+	if err != nil {
+		t.Fatalf("failed to allocate pattern, %s", err)
+	}
+	check := regexp.MustCompile(`^\+33 [67] \d\d$`)
+	for _, g := range []generator.Generator{legacy, v2} {
+		for range 100 {
+			if v, _ := g.Next(); !check.MatchString(v) {
+				t.Errorf("value '%s' of %s does not match %s", v, g.GetName(), check)
+			}
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package generators
+
+import (
+	"fmt"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const PHONE_GENERATOR_NAME = "phone"
+
+const PHONE_DEFAULT_LOCALE = "fr-FR"
+
+var PhoneOptions = []string{"kind", "format"}
+
+type PhoneKind string
+
+const (
+	PhoneKindMobile   PhoneKind = "mobile"
+	PhoneKindLandline PhoneKind = "landline"
+	PhoneKindTollFree PhoneKind = "tollfree"
+)
+
+type PhoneFormat int64
+
+const (
+	PhoneFormatInternational PhoneFormat = iota
+	PhoneFormatNational
+	PhoneFormatE164
+)
+
+// PhoneRange draws national significant numbers from a pattern, national and
+// international layouts consuming one digit per 'X'.
+type PhoneRange struct {
+	Pattern       string
+	National      string
+	International string
+}
+
+type PhonePlan struct {
+	CountryCode string
+	Ranges      map[PhoneKind][]PhoneRange
+}
+
+// PhonePlans maps the locales of the locale table to their numbering plans.
+var PhonePlans = map[string]PhonePlan{
+	"fr-FR": {
+		CountryCode: "33",
+		Ranges: map[PhoneKind][]PhoneRange{
+			PhoneKindMobile: {
+				{Pattern: "6{digit:8}", National: "0X XX XX XX XX", International: "+33 X XX XX XX XX"},
+				{Pattern: "7{3..8}{digit:7}", National: "0X XX XX XX XX", International: "+33 X XX XX XX XX"},
+			},
+			PhoneKindLandline: {
+				{Pattern: "{1..5}{digit:8}", National: "0X XX XX XX XX", International: "+33 X XX XX XX XX"},
+			},
+			PhoneKindTollFree: {
+				{Pattern: "80{0..5}{digit:6}", National: "0X XX XX XX XX", International: "+33 X XX XX XX XX"},
+			},
+		},
+	},
+	"en-UK": {
+		CountryCode: "44",
+		Ranges: map[PhoneKind][]PhoneRange{
+			PhoneKindMobile: {
+				{Pattern: "7{1..9!6}{digit:8}", National: "0XXXX XXXXXX", International: "+44 XXXX XXXXXX"},
+			},
+			PhoneKindLandline: {
+				{Pattern: "1{digit:9}", National: "0XXXX XXXXXX", International: "+44 XXXX XXXXXX"},
+				{Pattern: "2{0|3|4|8|9}{digit:8}", National: "0XX XXXX XXXX", International: "+44 XX XXXX XXXX"},
+			},
+			PhoneKindTollFree: {
+				{Pattern: "80{0|8}{digit:7}", National: "0XXX XXX XXXX", International: "+44 XXX XXX XXXX"},
+			},
+		},
+	},
+	"en-US": {
+		CountryCode: "1",
+		Ranges: map[PhoneKind][]PhoneRange{
+			// NANP does not tell mobile and landline numbers apart
+			PhoneKindMobile: {
+				{Pattern: "{2..9}{0..8}{digit:1}{2..9}{digit:6}", National: "(XXX) XXX-XXXX", International: "+1 XXX-XXX-XXXX"},
+			},
+			PhoneKindLandline: {
+				{Pattern: "{2..9}{0..8}{digit:1}{2..9}{digit:6}", National: "(XXX) XXX-XXXX", International: "+1 XXX-XXX-XXXX"},
+			},
+			PhoneKindTollFree: {
+				{Pattern: "8{00|33|44|55|66|77|88}{2..9}{digit:6}", National: "(XXX) XXX-XXXX", International: "+1 XXX-XXX-XXXX"},
+			},
+		},
+	},
+	"es-ES": {
+		CountryCode: "34",
+		Ranges: map[PhoneKind][]PhoneRange{
+			PhoneKindMobile: {
+				{Pattern: "6{digit:8}", National: "XXX XXX XXX", International: "+34 XXX XXX XXX"},
+				{Pattern: "7{1..4}{digit:7}", National: "XXX XXX XXX", International: "+34 XXX XXX XXX"},
+			},
+			PhoneKindLandline: {
+				{Pattern: "{8|9}{1..8}{digit:7}", National: "XXX XXX XXX", International: "+34 XXX XXX XXX"},
+			},
+			PhoneKindTollFree: {
+				{Pattern: "{800|900}{digit:6}", National: "XXX XXX XXX", International: "+34 XXX XXX XXX"},
+			},
+		},
+	},
+}
+
+// FormatPhoneLayout replaces each 'X' of layout with the next digit of nsn.
+func FormatPhoneLayout(layout, nsn string) (string, error) {
+	if n := strings.Count(layout, "X"); n != len(nsn) {
+		return "", fmt.Errorf("invalid phone layout '%s', expected %d digits but got '%s'", layout, n, nsn)
+	}
+	buf := strings.Builder{}
+	pos := 0
+	for _, r := range layout {
+		if r == 'X' {
+			buf.WriteByte(nsn[pos])
+			pos++
+		} else {
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String(), nil
+}
+
+type phoneRange struct {
+	PhoneRange
+	parts []PatternPart
+}
+
+type PhoneSettings struct {
+	Locale string
+	Kinds  []PhoneKind
+	Format PhoneFormat
+
+	ranges []phoneRange
+}
+
+// ParsePhone parses 'locale;kind=mobile|landline|tollfree;format=international|national|e164'.
+func ParsePhone(params ...any) (*PhoneSettings, error) {
+	spec, opts, err := ParseSpecOptions(PhoneOptions, params...)
+	if err != nil {
+		return nil, err
+	}
+	ret := &PhoneSettings{Locale: spec, Kinds: []PhoneKind{PhoneKindMobile, PhoneKindLandline}}
+	if len(ret.Locale) == 0 {
+		ret.Locale = PHONE_DEFAULT_LOCALE
+	}
+	plan, ok := PhonePlans[ret.Locale]
+	if !ok {
+		return nil, fmt.Errorf("unsupported phone locale '%s', expected one of %v", ret.Locale, slices.Sorted(maps.Keys(PhonePlans)))
+	}
+	if v, ok := opts["kind"]; ok {
+		ret.Kinds = []PhoneKind{}
+		for _, kind := range strings.Split(v, "|") {
+			kind := PhoneKind(strings.ToLower(strings.TrimSpace(kind)))
+			if _, ok := plan.Ranges[kind]; !ok {
+				return nil, fmt.Errorf("unknown phone kind '%s', expected one of mobile, landline or tollfree", kind)
+			}
+			ret.Kinds = append(ret.Kinds, kind)
+		}
+	}
+	switch strings.ToLower(opts["format"]) {
+	case "", "international":
+		ret.Format = PhoneFormatInternational
+	case "national":
+		ret.Format = PhoneFormatNational
+	case "e164":
+		ret.Format = PhoneFormatE164
+	default:
+		return nil, fmt.Errorf("invalid phone format '%s', expected one of international, national or e164", opts["format"])
+	}
+	for _, kind := range ret.Kinds {
+		for _, r := range plan.Ranges[kind] {
+			parts, err := ParsePatternParts(r.Pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s numbering plan, %s", ret.Locale, err)
+			}
+			ret.ranges = append(ret.ranges, phoneRange{PhoneRange: r, parts: parts})
+		}
+	}
+	return ret, nil
+}
+
+// Rand returns a number of one of the selected kinds, formatted.
+func (s *PhoneSettings) Rand(rng *rand.Rand) (string, error) {
+	r := s.ranges[rng.IntN(len(s.ranges))]
+	nsn := strings.Builder{}
+	for _, part := range r.parts {
+		nsn.WriteString(part.Rand(rng))
+	}
+	switch s.Format {
+	case PhoneFormatE164:
+		return "+" + PhonePlans[s.Locale].CountryCode + nsn.String(), nil
+	case PhoneFormatNational:
+		return FormatPhoneLayout(r.National, nsn.String())
+	}
+	return FormatPhoneLayout(r.International, nsn.String())
+}
+
+type PhoneGenerator struct {
+	*CacheGenerator
+
+	settings *PhoneSettings
+}
+
+func NewPhoneGenerator(options *generator.GeneratorOptions, settings *PhoneSettings) *PhoneGenerator {
+	return &PhoneGenerator{
+		CacheGenerator: NewCacheGenerator(options, PHONE_GENERATOR_NAME, func() (string, error) {
+			return settings.Rand(options.Rand)
+		}),
+		settings: settings,
+	}
+}
@@ -0,0 +1,120 @@
+package generators_test
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestPhonePlans(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	e164 := regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+	for locale, plan := range generators.PhonePlans {
+		for kind := range plan.Ranges {
+			for _, format := range []string{"international", "national", "e164"} {
+				settings, err := generators.ParsePhone("phone", fmt.Sprintf("%s;kind=%s;format=%s", locale, kind, format))
+				if err != nil {
+					t.Fatalf("failed to parse phone settings for %s %s, %s", locale, kind, err)
+				}
+				for range 50 {
+					number, err := settings.Rand(src)
+					if err != nil {
+						t.Fatalf("failed to generate %s %s number, %s", locale, kind, err)
+					}
+					if format == "e164" && !e164.MatchString(number) {
+						t.Fatalf("invalid E.164 number '%s' for %s %s", number, locale, kind)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestPhonePlanPrefixes(t *testing.T) {
+	span := func(prefix string, min, max int, exclude ...int) []string {
+		ret := []string{}
+		for i := min; i <= max; i++ {
+			if !slices.Contains(exclude, i) {
+				ret = append(ret, fmt.Sprintf("%s%d", prefix, i))
+			}
+		}
+		return ret
+	}
+	nanp := []string{}
+	for i := 2; i <= 9; i++ {
+		nanp = append(nanp, span(fmt.Sprint(i), 0, 8)...)
+	}
+	// every leading digits allowed by the plans, which numbers must all start with
+	prefixes := map[string]map[string][]string{
+		"fr-FR": {
+			"mobile":   append([]string{"6"}, span("7", 3, 8)...),
+			"landline": span("", 1, 5),
+			"tollfree": span("80", 0, 5),
+		},
+		"en-UK": {
+			"mobile":   span("7", 1, 9, 6),
+			"landline": {"1", "20", "23", "24", "28", "29"},
+			"tollfree": {"800", "808"},
+		},
+		"en-US": {
+			"mobile":   nanp,
+			"landline": nanp,
+			"tollfree": {"800", "833", "844", "855", "866", "877", "888"},
+		},
+		"es-ES": {
+			"mobile":   append([]string{"6"}, span("7", 1, 4)...),
+			"landline": append(span("8", 1, 8), span("9", 1, 8)...),
+			"tollfree": {"800", "900"},
+		},
+	}
+	src := rand.New(rand.NewPCG(1, 2))
+	for locale, plan := range generators.PhonePlans {
+		for kind := range plan.Ranges {
+			expected, ok := prefixes[locale][string(kind)]
+			if !ok {
+				t.Fatalf("missing expected prefixes for %s %s", locale, kind)
+			}
+			settings, err := generators.ParsePhone("phone", fmt.Sprintf("%s;kind=%s;format=e164", locale, kind))
+			if err != nil {
+				t.Fatalf("failed to parse phone settings for %s %s, %s", locale, kind, err)
+			}
+			seen := map[string]bool{}
+			for range 3000 {
+				number, err := settings.Rand(src)
+				if err != nil {
+					t.Fatalf("failed to generate %s %s number, %s", locale, kind, err)
+				}
+				nsn := strings.TrimPrefix(number, "+"+plan.CountryCode)
+				i := slices.IndexFunc(expected, func(p string) bool { return strings.HasPrefix(nsn, p) })
+				if i == -1 {
+					t.Fatalf("unexpected %s %s number '%s'", locale, kind, number)
+				}
+				seen[expected[i]] = true
+			}
+			for _, p := range expected {
+				if !seen[p] {
+					t.Errorf("expected some %s %s numbers to start with %s", locale, kind, p)
+				}
+			}
+		}
+	}
+}
+
+func TestFormatPhoneLayout(t *testing.T) {
+	if got, _ := generators.FormatPhoneLayout("(XXX) XXX-XXXX", "2125550123"); got != "(212) 555-0123" {
+		t.Errorf("invalid formatted number '%s'", got)
+	}
+	if _, err := generators.FormatPhoneLayout("0X XX", "61"); err == nil {
+		t.Errorf("expected an error for a number not matching its layout")
+	}
+	for _, tpl := range []string{"xx-XX", "fr-FR;kind=satellite", "fr-FR;format=rfc3966"} {
+		if _, err := generators.ParsePhone("phone", tpl); err == nil {
+			t.Errorf("expected an error for phone template '%s'", tpl)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package generators
 import (
 	"database/sql"
 	"fmt"
-	"math/rand/v2"
 
 	"github.com/welschmorgan/datagen/pkg/generator"
 )
@@ -21,12 +20,14 @@ type RandomDBRowGenerator struct {
 	tableName        string
 	tableFilterKey   string
 	tableFilterValue string
+	// locale restricts rows to those of a locale, all being drawn when empty
+	locale string
 
 	values []string
 	seen   []string
 }
 
-func NewRandomDBRowGenerator(options *generator.GeneratorOptions, db *sql.DB, tableName, tableFilterKey, tableFilterValue string) (*RandomDBRowGenerator, error) {
+func NewRandomDBRowGenerator(options *generator.GeneratorOptions, db *sql.DB, tableName, tableFilterKey, tableFilterValue, locale string) (*RandomDBRowGenerator, error) {
 	ret := &RandomDBRowGenerator{
 		options: options,
 		db:      db,
@@ -36,6 +37,7 @@ func NewRandomDBRowGenerator(options *generator.GeneratorOptions, db *sql.DB, ta
 		tableName:        tableName,
 		tableFilterKey:   tableFilterKey,
 		tableFilterValue: tableFilterValue,
+		locale:           locale,
 	}
 	ret.CacheGenerator = NewCacheGenerator(options, RANDOM_DB_ROW_GENERATOR_NAME, ret.next)
 	return ret, nil
@@ -43,13 +45,18 @@ func NewRandomDBRowGenerator(options *generator.GeneratorOptions, db *sql.DB, ta
 
 func (g *RandomDBRowGenerator) next() (string, error) {
 	if g.values == nil {
-		rawQuery := fmt.Sprintf("SELECT value FROM %s WHERE %s = ?", g.tableName, g.tableFilterKey)
+		rawQuery := fmt.Sprintf("SELECT value FROM %s WHERE %s = ? ORDER BY id", g.tableName, g.tableFilterKey)
+		params := []any{g.tableFilterValue}
+		if len(g.locale) > 0 {
+			rawQuery = fmt.Sprintf("SELECT p.value FROM %s p JOIN locale l ON l.id = p.locale_id WHERE p.%s = ? AND l.name = ? ORDER BY p.id", g.tableName, g.tableFilterKey)
+			params = append(params, g.locale)
+		}
 		query, err := g.db.Prepare(rawQuery)
 		if err != nil {
 			return "", err
 		}
 
-		rows, err := query.Query(g.tableFilterValue)
+		rows, err := query.Query(params...)
 		if err != nil {
 			return "", err
 		}
@@ -61,9 +68,9 @@ func (g *RandomDBRowGenerator) next() (string, error) {
 			g.values = append(g.values, value)
 		}
 		if len(g.values) == 0 {
-			return "", fmt.Errorf("invalid random_row generator, filter matches nothing: '%s' (params=['%s'])", rawQuery, g.tableFilterValue)
+			return "", fmt.Errorf("invalid random_row generator, filter matches nothing: '%s' (params=%v)", rawQuery, params)
 		}
 	}
-	value_id := rand.Int() % len(g.values)
+	value_id := g.options.Rand.IntN(len(g.values))
 	return g.values[value_id], nil
 }
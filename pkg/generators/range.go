@@ -11,7 +11,7 @@ type IntRangeGenerator struct {
 func NewIntRangeGenerator(options *generator.GeneratorOptions, range_ Range[int64]) *IntRangeGenerator {
 	return &IntRangeGenerator{
 		CacheGenerator: NewCacheGenerator(options, INT_RANGE_GENERATOR_NAME, func() (string, error) {
-			return range_.RandPadded(), nil
+			return range_.RandPadded(options.Rand), nil
 		}),
 	}
 }
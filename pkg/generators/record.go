@@ -0,0 +1,472 @@
+package generators
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+var EntityFieldName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var entityFuncCall = regexp.MustCompile(`^([a-z_]+)\((.*)\)$`)
+
+// EntityField is a field of a record, either drawn from Resource or derived
+// from the other fields of the record.
+type EntityField struct {
+	Name     string
+	Resource string
+	Derived  Derivation
+}
+
+// Derivation computes a field from the record being built, which already
+// holds the values of the fields listed by Deps.
+type Derivation interface {
+	Deps() []string
+	Resources() []string
+	Derive(r *Record, options *generator.GeneratorOptions, resGetter func(name string) generator.Generator) (string, error)
+}
+
+// DerivedFunc computes a field from the values of its arguments.
+type DerivedFunc func(args []string, options *generator.GeneratorOptions) (string, error)
+
+// DerivedFuncs are the functions usable as 'name(field, ...)' in entities.
+var DerivedFuncs = map[string]DerivedFunc{
+	"birthdate": BirthDate,
+	"age":       Age,
+	"identicon": IdenticonOf,
+}
+
+// BirthDate returns a date at which someone born is args[0] years old today.
+func BirthDate(args []string, options *generator.GeneratorOptions) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("invalid birthdate arguments, expected an age but got %v", args)
+	}
+	if generator.IsNull(args[0]) {
+		return generator.NULL_VALUE, nil
+	}
+	age, err := strconv.Atoi(strings.TrimSpace(args[0]))
+	if err != nil || age < 0 {
+		return "", fmt.Errorf("invalid birthdate age '%s', expected a positive number", args[0])
+	}
+	now := options.Now()
+	lo, hi := now.AddDate(-age-1, 0, 1), now.AddDate(-age, 0, 0)
+	days := int(hi.Sub(lo).Hours() / 24)
+	return lo.AddDate(0, 0, options.Rand.IntN(days+1)).Format(DATETIME_LAYOUT_DATE), nil
+}
+
+// Age returns the number of years elapsed since the date args[0].
+func Age(args []string, options *generator.GeneratorOptions) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("invalid age arguments, expected a date but got %v", args)
+	}
+	if generator.IsNull(args[0]) {
+		return generator.NULL_VALUE, nil
+	}
+	for _, layout := range datetimeBoundLayouts {
+		if birth, err := time.Parse(layout, strings.TrimSpace(args[0])); err == nil {
+			now := options.Now()
+			age := now.Year() - birth.Year()
+			if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+				age--
+			}
+			return strconv.Itoa(age), nil
+		}
+	}
+	return "", fmt.Errorf("invalid age date '%s'", args[0])
+}
+
+// IdenticonOf returns the data URI of the identicon of args[0], so that a
+// record gets the picture of one of its own fields, such as its email.
+func IdenticonOf(args []string, options *generator.GeneratorOptions) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("invalid identicon arguments, expected a field but got %v", args)
+	}
+	if generator.IsNull(args[0]) {
+		return generator.NULL_VALUE, nil
+	}
+	settings := &ImageSettings{Kind: ImageKindIdenticon, Width: IMAGE_DEFAULT_SIZE, Height: IMAGE_DEFAULT_SIZE, Format: "png"}
+	return settings.Output(options.Rand, args[0])
+}
+
+// TemplateDerivation renders a template whose references are either fields
+// of the record or resources, both accepting filters.
+type TemplateDerivation struct {
+	template string
+	parts    []TemplatePart
+	fields   []string
+	filters  [][]Filter
+}
+
+func (d *TemplateDerivation) Deps() []string {
+	ret := []string{}
+	for _, field := range d.fields {
+		if len(field) > 0 && !slices.Contains(ret, field) {
+			ret = append(ret, field)
+		}
+	}
+	return ret
+}
+
+func (d *TemplateDerivation) Resources() []string {
+	ret := []string{}
+	for i, part := range d.parts {
+		if part.IsReference && len(d.fields[i]) == 0 {
+			ret = append(ret, part.Value)
+		}
+	}
+	return ret
+}
+
+func (d *TemplateDerivation) Derive(r *Record, options *generator.GeneratorOptions, resGetter func(name string) generator.Generator) (string, error) {
+	buf := strings.Builder{}
+	for i, part := range d.parts {
+		if !part.IsReference {
+			buf.WriteString(part.Value)
+			continue
+		}
+		var value string
+		if len(d.fields[i]) > 0 {
+			value, _ = r.Get(d.fields[i])
+			value = ApplyFilters(value, d.filters[i])
+		} else {
+			gen := resGetter(part.Value)
+			if gen == nil {
+				return "", fmt.Errorf("unknown resource '%s' in '%s'", part.Value, d.template)
+			}
+			var err error
+			if value, err = SampleValue(gen); err != nil {
+				return "", err
+			}
+		}
+		// a missing part makes the whole value missing
+		if generator.IsNull(value) {
+			return generator.NULL_VALUE, nil
+		}
+		buf.WriteString(value)
+	}
+	return buf.String(), nil
+}
+
+// FuncDerivation calls one of DerivedFuncs with the values of its arguments.
+type FuncDerivation struct {
+	name string
+	args []string
+}
+
+func (d *FuncDerivation) Deps() []string {
+	return d.args
+}
+
+func (d *FuncDerivation) Resources() []string {
+	return []string{}
+}
+
+func (d *FuncDerivation) Derive(r *Record, options *generator.GeneratorOptions, resGetter func(name string) generator.Generator) (string, error) {
+	args := []string{}
+	for _, field := range d.args {
+		value, _ := r.Get(field)
+		args = append(args, value)
+	}
+	return DerivedFuncs[d.name](args, options)
+}
+
+// splitEntity splits declarations on commas, except within quotes and
+// parentheses.
+func splitEntity(body string) []string {
+	ret := []string{}
+	accu := strings.Builder{}
+	quoted, escaped, depth := false, false, 0
+	for _, ch := range body {
+		switch {
+		case escaped:
+			escaped = false
+		case ch == '\\':
+			escaped = true
+		case ch == '\'':
+			quoted = !quoted
+		case !quoted && ch == '(':
+			depth++
+		case !quoted && ch == ')':
+			depth--
+		case !quoted && depth == 0 && ch == ',':
+			ret = append(ret, accu.String())
+			accu.Reset()
+			continue
+		}
+		accu.WriteRune(ch)
+	}
+	return append(ret, accu.String())
+}
+
+// parseDerivation parses the expression of a field, returning nil when it is
+// drawn from a resource. Expressions are either:
+//   - a quoted template such as '{firstName|lower}.{lastName}@example.com'
+//   - a function call such as 'birthdate(age)'
+//   - another field, optionally filtered, such as 'lastName|upper'
+func parseDerivation(name, expr string, fields []string) (Derivation, error) {
+	template := ""
+	switch {
+	case strings.HasPrefix(expr, "'"):
+		if len(expr) < 2 || !strings.HasSuffix(expr, "'") || strings.HasSuffix(expr, "\\'") {
+			return nil, fmt.Errorf("invalid template %s, missing closing quote", expr)
+		}
+		template = expr[1 : len(expr)-1]
+	case entityFuncCall.MatchString(expr):
+		m := entityFuncCall.FindStringSubmatch(expr)
+		if _, ok := DerivedFuncs[m[1]]; !ok {
+			return nil, fmt.Errorf("unknown function '%s', expected one of %v", m[1], slices.Sorted(maps.Keys(DerivedFuncs)))
+		}
+		ret := &FuncDerivation{name: m[1]}
+		for _, arg := range strings.Split(m[2], ",") {
+			arg = strings.TrimSpace(arg)
+			if !slices.Contains(fields, arg) {
+				return nil, fmt.Errorf("invalid argument '%s' to %s, expected a field", arg, m[1])
+			}
+			ret.args = append(ret.args, arg)
+		}
+		return ret, nil
+	default:
+		// 'id: id' draws from the resource of the same name
+		base, _, _ := strings.Cut(expr, "|")
+		if base = strings.TrimSpace(base); base == name || !slices.Contains(fields, base) {
+			return nil, nil
+		}
+		template = "{" + expr + "}"
+	}
+	_, parts, err := ParseTemplate(TEMPLATE_GENERATOR_NAME, template)
+	if err != nil {
+		return nil, err
+	}
+	ret := &TemplateDerivation{template: template, parts: parts, fields: make([]string, len(parts)), filters: make([][]Filter, len(parts))}
+	for i, part := range parts {
+		if !part.IsReference {
+			continue
+		}
+		base, filters, err := ParseFilterChain(part.Value)
+		if err != nil {
+			return nil, err
+		}
+		// fields take precedence over resources of the same name
+		if slices.Contains(fields, base) {
+			ret.fields[i], ret.filters[i] = base, filters
+		}
+	}
+	return ret, nil
+}
+
+// EntitySchema holds the fields of an entity in declaration order, Order
+// listing their indices in dependency order.
+type EntitySchema struct {
+	Fields []EntityField
+	Order  []int
+}
+
+// ParseEntity parses '{id: id.sequence, firstName: person.firstName|title,
+// email: '{firstName}@example.com'}', braces being optional. Fields are
+// drawn from resources unless they reference other fields, see
+// parseDerivation.
+func ParseEntity(spec string) (*EntitySchema, error) {
+	body := strings.TrimSpace(spec)
+	if strings.HasPrefix(body, "{") != strings.HasSuffix(body, "}") {
+		return nil, fmt.Errorf("invalid entity '%s', unbalanced braces", spec)
+	}
+	body = strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(body, "{"), "}"))
+	names, exprs := []string{}, []string{}
+	for _, decl := range splitEntity(body) {
+		name, expr, found := strings.Cut(decl, ":")
+		name, expr = strings.TrimSpace(name), strings.TrimSpace(expr)
+		if !found || len(expr) == 0 {
+			return nil, fmt.Errorf("invalid entity field '%s', expected 'name: resource'", strings.TrimSpace(decl))
+		}
+		if !EntityFieldName.MatchString(name) {
+			return nil, fmt.Errorf("invalid entity field name '%s'", name)
+		}
+		if slices.Contains(names, name) {
+			return nil, fmt.Errorf("duplicate entity field '%s'", name)
+		}
+		names, exprs = append(names, name), append(exprs, expr)
+	}
+	ret := []EntityField{}
+	for i, name := range names {
+		derived, err := parseDerivation(name, exprs[i], names)
+		if err != nil {
+			return nil, fmt.Errorf("invalid entity field '%s', %s", name, err)
+		}
+		if derived != nil {
+			ret = append(ret, EntityField{Name: name, Derived: derived})
+		} else {
+			ret = append(ret, EntityField{Name: name, Resource: exprs[i]})
+		}
+	}
+	order, err := EntityOrder(ret)
+	if err != nil {
+		return nil, err
+	}
+	return &EntitySchema{Fields: ret, Order: order}, nil
+}
+
+// EntityOrder sorts fields so that each one comes after those it depends on,
+// keeping the declaration order otherwise, and reports dependency cycles.
+func EntityOrder(fields []EntityField) ([]int, error) {
+	index := map[string]int{}
+	for i, f := range fields {
+		index[f.Name] = i
+	}
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, len(fields))
+	order := []int{}
+	path := []string{}
+	var visit func(i int) error
+	visit = func(i int) error {
+		switch state[i] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := append(path[slices.Index(path, fields[i].Name):], fields[i].Name)
+			return fmt.Errorf("cycle between entity fields %s", strings.Join(cycle, " -> "))
+		}
+		state[i] = visiting
+		path = append(path, fields[i].Name)
+		if fields[i].Derived != nil {
+			for _, dep := range fields[i].Derived.Deps() {
+				j, ok := index[dep]
+				if !ok {
+					return fmt.Errorf("unknown field '%s' referenced by '%s'", dep, fields[i].Name)
+				}
+				if err := visit(j); err != nil {
+					return err
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		order = append(order, i)
+		return nil
+	}
+	for i := range fields {
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
+
+// Record holds the values of the fields of an entity, in declaration order.
+type Record struct {
+	Fields []string
+	Values []string
+}
+
+func (r *Record) Get(field string) (string, bool) {
+	if i := slices.Index(r.Fields, field); i != -1 {
+		return r.Values[i], true
+	}
+	return "", false
+}
+
+// MarshalJSON renders the record as an object keeping the field order, null
+// values being rendered as JSON nulls.
+func (r *Record) MarshalJSON() ([]byte, error) {
+	buf := bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, field := range r.Fields {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, err := json.Marshal(field)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		if generator.IsNull(r.Values[i]) {
+			buf.WriteString("null")
+			continue
+		}
+		value, err := json.Marshal(r.Values[i])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// RecordGenerator draws the fields of an entity from their resources, then
+// derives the others in dependency order.
+type RecordGenerator struct {
+	name      string
+	options   *generator.GeneratorOptions
+	fields    []EntityField
+	order     []int
+	resGetter func(name string) generator.Generator
+}
+
+func NewRecordGenerator(options *generator.GeneratorOptions, name string, schema *EntitySchema, resGetter func(name string) generator.Generator) *RecordGenerator {
+	return &RecordGenerator{
+		name:      name,
+		options:   options,
+		fields:    schema.Fields,
+		order:     schema.Order,
+		resGetter: resGetter,
+	}
+}
+
+func (g *RecordGenerator) GetName() string {
+	return g.name
+}
+
+func (g *RecordGenerator) Fields() []string {
+	ret := []string{}
+	for _, f := range g.fields {
+		ret = append(ret, f.Name)
+	}
+	return ret
+}
+
+func (g *RecordGenerator) Validate() error {
+	for _, f := range g.fields {
+		resources := []string{f.Resource}
+		if f.Derived != nil {
+			resources = f.Derived.Resources()
+		}
+		for _, resource := range resources {
+			if g.resGetter(resource) == nil {
+				return fmt.Errorf("unknown resource '%s' for field '%s'", resource, f.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (g *RecordGenerator) Next() (*Record, error) {
+	ret := &Record{Fields: g.Fields(), Values: make([]string, len(g.fields))}
+	for _, i := range g.order {
+		f := g.fields[i]
+		var err error
+		if f.Derived != nil {
+			ret.Values[i], err = f.Derived.Derive(ret, g.options, g.resGetter)
+		} else if gen := g.resGetter(f.Resource); gen == nil {
+			err = fmt.Errorf("unknown resource '%s'", f.Resource)
+		} else {
+			ret.Values[i], err = gen.Next()
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate field '%s', %s", f.Name, err)
+		}
+	}
+	return ret, nil
+}
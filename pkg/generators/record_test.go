@@ -0,0 +1,192 @@
+package generators_test
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestParseEntity(t *testing.T) {
+	schema, err := generators.ParseEntity("{id: id.sequence, lastName: person.lastName|upper}")
+	if err != nil {
+		t.Fatalf("failed to parse entity, %s", err)
+	}
+	expected := []generators.EntityField{{Name: "id", Resource: "id.sequence"}, {Name: "lastName", Resource: "person.lastName|upper"}}
+	if len(schema.Fields) != len(expected) || schema.Fields[0] != expected[0] || schema.Fields[1] != expected[1] {
+		t.Errorf("expected %v but got %v", expected, schema.Fields)
+	}
+	if schema, err := generators.ParseEntity("{email: '{name}@example.com', name: person.firstName}"); err != nil || !slices.Equal(schema.Order, []int{1, 0}) {
+		t.Errorf("expected fields to be ordered after those they depend on, got %v (%v)", schema, err)
+	}
+	for _, spec := range []string{"{id: id.sequence", "id", "id:", "1st: id.sequence", "id: id.sequence, id: id.uuid"} {
+		if _, err := generators.ParseEntity(spec); err == nil {
+			t.Errorf("expected entity '%s' to be invalid", spec)
+		}
+	}
+}
+
+func TestRecordGenerator(t *testing.T) {
+	options := generator.NewGeneratorOptions()
+	resources := map[string]generator.Generator{
+		"id":   generators.NewSequenceGenerator(options, mustParseSequence(t, "%d")),
+		"code": generators.NewSequenceGenerator(options, mustParseSequence(t, "C%02d;start=10")),
+		"none": generators.NewNullableGenerator(options, "id", 1, nil),
+	}
+	schema, err := generators.ParseEntity("{id: id, code: code, missing: none}")
+	if err != nil {
+		t.Fatalf("failed to parse entity, %s", err)
+	}
+	gen := generators.NewRecordGenerator(options, "test", schema, func(name string) generator.Generator {
+		return resources[name]
+	})
+	if err := gen.Validate(); err != nil {
+		t.Fatalf("expected record generator to be valid, %s", err)
+	}
+	for _, expected := range []string{`{"id":"1","code":"C10","missing":null}`, `{"id":"2","code":"C11","missing":null}`} {
+		record, err := gen.Next()
+		if err != nil {
+			t.Fatalf("failed to generate record, %s", err)
+		}
+		data, err := json.Marshal(record)
+		if err != nil {
+			t.Fatalf("failed to marshal record, %s", err)
+		}
+		if string(data) != expected {
+			t.Errorf("expected %s but got %s", expected, data)
+		}
+	}
+	schema, _ = generators.ParseEntity("{id: unknown}")
+	gen = generators.NewRecordGenerator(options, "test", schema, func(name string) generator.Generator { return resources[name] })
+	if err := gen.Validate(); err == nil {
+		t.Errorf("expected unknown resources to be reported")
+	}
+}
+
+func TestDerivedEntityFields(t *testing.T) {
+	options := generator.NewGeneratorOptions()
+	options.SetRandSeed(1)
+	resources := map[string]generator.Generator{
+		"first":  generators.NewSequenceGenerator(options, mustParseSequence(t, "jean %d")),
+		"last":   generators.NewSequenceGenerator(options, mustParseSequence(t, "DUPONT%d;start=5")),
+		"age":    generators.NewSequenceGenerator(options, mustParseSequence(t, "%d;start=30")),
+		"domain": generators.NewSequenceGenerator(options, mustParseSequence(t, "example%d.com")),
+	}
+	// fields are declared before those they depend on are
+	schema, err := generators.ParseEntity("{email: '{firstName|slug}.{lastName|lower}@{domain}', fullName: '{firstName|title} {lastName}', firstName: first, lastName: last, upper: fullName|upper, birth: birthdate(age), age: age, years: age(birth)}")
+	if err != nil {
+		t.Fatalf("failed to parse entity, %s", err)
+	}
+	gen := generators.NewRecordGenerator(options, "test", schema, func(name string) generator.Generator {
+		return resources[name]
+	})
+	if err := gen.Validate(); err != nil {
+		t.Fatalf("expected record generator to be valid, %s", err)
+	}
+	record, err := gen.Next()
+	if err != nil {
+		t.Fatalf("failed to generate record, %s", err)
+	}
+	for field, expected := range map[string]string{
+		"email":    "jean-1.dupont5@example1.com",
+		"fullName": "Jean 1 DUPONT5",
+		"upper":    "JEAN 1 DUPONT5",
+		"age":      "30",
+		"years":    "30",
+	} {
+		if v, _ := record.Get(field); v != expected {
+			t.Errorf("expected %s to be '%s' but got '%s'", field, expected, v)
+		}
+	}
+	if birth, _ := record.Get("birth"); !strings.HasPrefix(birth, "1993-") && !strings.HasPrefix(birth, "1994-01-01") {
+		t.Errorf("expected a birth date 30 years before %s but got %s", generator.SEEDED_NOW.Format("2006-01-02"), birth)
+	}
+}
+
+func TestDerivedEntityFieldsPropagateNulls(t *testing.T) {
+	options := generator.NewGeneratorOptions()
+	var resources map[string]generator.Generator
+	resGetter := func(name string) generator.Generator {
+		return resources[name]
+	}
+	resources = map[string]generator.Generator{
+		"first":  generators.NewSequenceGenerator(options, mustParseSequence(t, "jean%d")),
+		"last":   generators.NewNullableGenerator(options, "first", 1, resGetter),
+		"domain": generators.NewSequenceGenerator(options, mustParseSequence(t, "example%d.com")),
+	}
+	schema, err := generators.ParseEntity("{firstName: first, lastName: last, email: '{firstName|slug}.{lastName|slug}@{domain}', alias: lastName|upper, age: birthdate(lastName)}")
+	if err != nil {
+		t.Fatalf("failed to parse entity, %s", err)
+	}
+	gen := generators.NewRecordGenerator(options, "test", schema, resGetter)
+	record, err := gen.Next()
+	if err != nil {
+		t.Fatalf("failed to generate record, %s", err)
+	}
+	for _, field := range []string{"lastName", "email", "alias", "age"} {
+		if v, _ := record.Get(field); !generator.IsNull(v) {
+			t.Errorf("expected %s to be null but got '%s'", field, v)
+		}
+	}
+}
+
+func TestEntityCycles(t *testing.T) {
+	for spec, expected := range map[string]string{
+		"{a: b|upper, b: '{c}!', c: a}": "cycle between entity fields a -> b -> c -> a",
+		"{a: '{a}'}":                    "cycle between entity fields a -> a",
+		"{a: birthdate(b), b: age(a)}":  "cycle between entity fields a -> b -> a",
+	} {
+		_, err := generators.ParseEntity(spec)
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected '%s' to fail with '%s' but got %v", spec, expected, err)
+		}
+	}
+	schema, err := generators.ParseEntity("{id: id, label: 'a, b (c)', n: birthdate(id)}")
+	if err != nil {
+		t.Fatalf("failed to parse entity, %s", err)
+	}
+	if len(schema.Fields) != 3 || schema.Fields[0].Derived != nil || schema.Fields[1].Derived == nil || schema.Fields[2].Derived == nil {
+		t.Errorf("expected quotes and parentheses to be kept within fields, got %v", schema.Fields)
+	}
+	for _, spec := range []string{"{a: 'unterminated}", "{a: unknown(a)}", "{a: birthdate(b)}"} {
+		if _, err := generators.ParseEntity(spec); err == nil {
+			t.Errorf("expected entity '%s' to be invalid", spec)
+		}
+	}
+}
+
+func TestDerivedIdenticon(t *testing.T) {
+	options := generator.NewGeneratorOptions()
+	resources := map[string]generator.Generator{
+		"email": generators.NewSequenceGenerator(options, mustParseSequence(t, "user%d@example.com;limit=3;wrap")),
+	}
+	schema, err := generators.ParseEntity("{email: email, avatar: identicon(email)}")
+	if err != nil {
+		t.Fatalf("failed to parse entity, %s", err)
+	}
+	gen := generators.NewRecordGenerator(options, "test", schema, func(name string) generator.Generator {
+		return resources[name]
+	})
+	avatars := map[string]string{}
+	for range 9 {
+		record, err := gen.Next()
+		if err != nil {
+			t.Fatalf("failed to generate record, %s", err)
+		}
+		email, _ := record.Get("email")
+		avatar, _ := record.Get("avatar")
+		if !strings.HasPrefix(avatar, "data:image/png;base64,") {
+			t.Fatalf("invalid avatar '%.40s'", avatar)
+		}
+		if prev, ok := avatars[email]; ok && prev != avatar {
+			t.Errorf("expected equal avatars for email '%s'", email)
+		}
+		avatars[email] = avatar
+	}
+	if len(avatars) != 3 || avatars["user1@example.com"] == avatars["user2@example.com"] {
+		t.Errorf("expected a distinct avatar per email but got %d", len(avatars))
+	}
+}
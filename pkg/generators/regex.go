@@ -0,0 +1,182 @@
+package generators
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"regexp/syntax"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const REGEX_GENERATOR_NAME = "regex"
+
+// REGEX_DEFAULT_MAX_REPEAT bounds unbounded quantifiers such as '*' and '+'.
+const REGEX_DEFAULT_MAX_REPEAT = 10
+
+var RegexOptions = []string{"max_repeat"}
+
+// printable ASCII, used for '.' and to narrow down negated classes
+var regexPrintable = []rune{0x20, 0x7e}
+
+type RegexGenerator struct {
+	*CacheGenerator
+
+	expr      string
+	re        *syntax.Regexp
+	maxRepeat int
+}
+
+// splitRegexOptions splits 'regex;key=value' into the expression and its
+// options, only trailing segments naming one of RegexOptions being options so
+// that expressions such as '[^;]+' are kept whole.
+func splitRegexOptions(s string) (expr string, opts map[string]string) {
+	opts = map[string]string{}
+	for {
+		pos := strings.LastIndex(s, ";")
+		if pos == -1 {
+			break
+		}
+		key, value, _ := strings.Cut(s[pos+1:], "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		if !slices.Contains(RegexOptions, key) {
+			break
+		}
+		if _, ok := opts[key]; !ok {
+			opts[key] = strings.TrimSpace(value)
+		}
+		s = s[:pos]
+	}
+	return strings.TrimSpace(s), opts
+}
+
+// ParseRegex parses 'regex;max_repeat=N'.
+func ParseRegex(params ...any) (expr string, re *syntax.Regexp, maxRepeat int, err error) {
+	if len(params) < 2 {
+		return "", nil, 0, fmt.Errorf("invalid arguments, expected ['generator_name', 'regex'] but got %v", params)
+	}
+	args, err := ParseStrings(len(params), params...)
+	if err != nil {
+		return "", nil, 0, err
+	}
+	// expressions such as '(?:a)' use the ':' argument separator
+	expr, opts := splitRegexOptions(strings.Join(args[1:], ":"))
+	if len(expr) == 0 {
+		return "", nil, 0, fmt.Errorf("invalid arguments, expected ['generator_name', 'regex'] but got %v", params)
+	}
+	maxRepeat = REGEX_DEFAULT_MAX_REPEAT
+	if v, ok := opts["max_repeat"]; ok {
+		if maxRepeat, err = strconv.Atoi(v); err != nil || maxRepeat < 0 {
+			return "", nil, 0, fmt.Errorf("invalid max_repeat '%s', expected a positive number", v)
+		}
+	}
+	if re, err = syntax.Parse(expr, syntax.Perl); err != nil {
+		return "", nil, 0, fmt.Errorf("invalid regex '%s', %s", expr, err)
+	}
+	return expr, re, maxRepeat, nil
+}
+
+func NewRegexGenerator(options *generator.GeneratorOptions, expr string, re *syntax.Regexp, maxRepeat int) *RegexGenerator {
+	ret := &RegexGenerator{
+		expr:      expr,
+		re:        re,
+		maxRepeat: maxRepeat,
+	}
+	ret.CacheGenerator = NewCacheGenerator(options, REGEX_GENERATOR_NAME, func() (string, error) {
+		buf := strings.Builder{}
+		if err := ret.generate(&buf, ret.re); err != nil {
+			return "", err
+		}
+		return buf.String(), nil
+	})
+	return ret
+}
+
+func (g *RegexGenerator) generate(buf *strings.Builder, re *syntax.Regexp) error {
+	switch re.Op {
+	case syntax.OpNoMatch:
+		return fmt.Errorf("regex '%s' cannot match anything", g.expr)
+	case syntax.OpEmptyMatch, syntax.OpBeginLine, syntax.OpEndLine, syntax.OpBeginText, syntax.OpEndText, syntax.OpWordBoundary, syntax.OpNoWordBoundary:
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			if re.Flags&syntax.FoldCase != 0 {
+				if g.options.Rand.IntN(2) == 0 {
+					r = unicode.ToUpper(r)
+				} else {
+					r = unicode.ToLower(r)
+				}
+			}
+			buf.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		if len(re.Rune) == 0 {
+			return fmt.Errorf("regex '%s' contains an empty character class", g.expr)
+		}
+		buf.WriteRune(randRuneInClass(g.options.Rand, re.Rune))
+	case syntax.OpAnyCharNotNL, syntax.OpAnyChar:
+		buf.WriteRune(randRuneInClass(g.options.Rand, regexPrintable))
+	case syntax.OpCapture:
+		return g.generate(buf, re.Sub[0])
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		lo, hi := 0, g.maxRepeat
+		switch re.Op {
+		case syntax.OpPlus:
+			lo = 1
+		case syntax.OpQuest:
+			hi = 1
+		case syntax.OpRepeat:
+			lo, hi = re.Min, re.Max
+			if hi == -1 {
+				hi = lo + g.maxRepeat
+			}
+		}
+		hi = max(lo, hi)
+		for range lo + g.options.Rand.IntN(hi-lo+1) {
+			if err := g.generate(buf, re.Sub[0]); err != nil {
+				return err
+			}
+		}
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			if err := g.generate(buf, sub); err != nil {
+				return err
+			}
+		}
+	case syntax.OpAlternate:
+		return g.generate(buf, re.Sub[g.options.Rand.IntN(len(re.Sub))])
+	default:
+		return fmt.Errorf("unsupported regex operation '%s' in '%s'", re.Op, g.expr)
+	}
+	return nil
+}
+
+// randRuneInClass picks a rune from the given [lo, hi] pairs, preferring
+// printable ASCII so that negated classes don't yield random unicode.
+func randRuneInClass(rng *rand.Rand, ranges []rune) rune {
+	printable := []rune{}
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := max(ranges[i], regexPrintable[0]), min(ranges[i+1], regexPrintable[1])
+		if lo <= hi {
+			printable = append(printable, lo, hi)
+		}
+	}
+	if len(printable) > 0 {
+		ranges = printable
+	}
+	var total int64
+	for i := 0; i+1 < len(ranges); i += 2 {
+		total += int64(ranges[i+1]-ranges[i]) + 1
+	}
+	n := rng.Int64N(total)
+	for i := 0; i+1 < len(ranges); i += 2 {
+		size := int64(ranges[i+1]-ranges[i]) + 1
+		if n < size {
+			return ranges[i] + rune(n)
+		}
+		n -= size
+	}
+	return ranges[len(ranges)-1]
+}
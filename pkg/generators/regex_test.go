@@ -0,0 +1,59 @@
+package generators_test
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestRegexGenerator(t *testing.T) {
+	exprs := []string{
+		`[A-Z]{2}-\d{3}-[A-Z]{2}`,
+		`(?i:sku)_[0-9a-f]{4,8}(-v\d+)?`,
+		`(foo|bar|baz)+\.[^a-z]{3}`,
+		`\w+@example\.(com|org)`,
+		`[^;]{1,5};[a-z]{2}`,
+	}
+	for _, expr := range exprs {
+		_, re, maxRepeat, err := generators.ParseRegex("regex", expr)
+		if err != nil {
+			t.Fatalf("failed to parse regex '%s', %s", expr, err)
+		}
+		g := generators.NewRegexGenerator(generator.NewGeneratorOptions(), expr, re, maxRepeat)
+		check := regexp.MustCompile("^(?:" + expr + ")$")
+		for range 200 {
+			v, err := g.Next()
+			if err != nil {
+				t.Fatalf("failed to generate value for '%s', %s", expr, err)
+			}
+			if !check.MatchString(v) {
+				t.Errorf("value '%s' does not match regex '%s'", v, expr)
+			}
+		}
+	}
+}
+
+func TestParseRegexOptions(t *testing.T) {
+	for _, c := range []struct {
+		params    []any
+		expr      string
+		maxRepeat int
+	}{
+		{[]any{"regex", "[^;]+;max_repeat=3"}, "[^;]+", 3},
+		{[]any{"regex", "a;b"}, "a;b", generators.REGEX_DEFAULT_MAX_REPEAT},
+		{[]any{"regex", "(?", "i)k;v=1;max_repeat=2"}, "(?:i)k;v=1", 2},
+	} {
+		expr, _, maxRepeat, err := generators.ParseRegex(c.params...)
+		if err != nil {
+			t.Fatalf("failed to parse regex %v, %s", c.params, err)
+		}
+		if expr != c.expr || maxRepeat != c.maxRepeat {
+			t.Errorf("expected %v to parse as '%s' repeated %d times but got '%s' and %d", c.params, c.expr, c.maxRepeat, expr, maxRepeat)
+		}
+	}
+	if _, _, _, err := generators.ParseRegex("regex", "a+;max_repeat=-1"); err == nil {
+		t.Errorf("expected an error for a negative max_repeat")
+	}
+}
@@ -0,0 +1,218 @@
+package generators
+
+import (
+	"encoding/base32"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const SECRET_GENERATOR_NAME = "secret"
+
+const SECRET_MAX_LENGTH = 128
+const SECRET_MAX_TOKEN_BYTES = 256
+
+// SecretAmbiguous lists characters easily mistaken for one another.
+const SecretAmbiguous = "Il1|O0o"
+
+var SecretOptions = []string{"min", "max", "classes", "no_ambiguous", "min_entropy", "encoding", "bytes", "prefix"}
+
+// SecretClasses are the character classes a password policy can require.
+var SecretClasses = map[string]string{
+	"lower":  PatternClasses["lower"],
+	"upper":  PatternClasses["upper"],
+	"digit":  PatternClasses["digit"],
+	"symbol": "!#$%&()*+,-./:;<=>?@[]^_{}~",
+}
+
+var secretEncodings = map[string]func([]byte) string{
+	"hex":       hex.EncodeToString,
+	"base32":    base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString,
+	"base64url": base64.RawURLEncoding.EncodeToString,
+}
+
+type SecretKind int64
+
+const (
+	SecretKindPassword SecretKind = iota
+	SecretKindToken
+)
+
+type SecretSettings struct {
+	Kind SecretKind
+
+	// password policy, Classes holding the character set of each required class
+	Min     int
+	Max     int
+	Classes []string
+	Charset []rune
+
+	// token format
+	Encoding string
+	Bytes    int
+	Prefix   string
+}
+
+// ParseSecret parses either:
+//   - 'password;min=N;max=N;classes=lower|upper|digit|symbol;no_ambiguous;min_entropy=BITS'
+//   - 'token;encoding=hex|base32|base64url;bytes=N;prefix=sk_'
+func ParseSecret(params ...any) (*SecretSettings, error) {
+	spec, opts, err := ParseSpecOptions(SecretOptions, params...)
+	if err != nil {
+		return nil, err
+	}
+	parseInt := func(key string, dst *int, lo, hi int) error {
+		if v, ok := opts[key]; ok {
+			if *dst, err = strconv.Atoi(v); err != nil || *dst < lo || *dst > hi {
+				return fmt.Errorf("invalid secret %s '%s', expected a number between %d and %d", key, v, lo, hi)
+			}
+		}
+		return nil
+	}
+	switch strings.ToLower(spec) {
+	case "", "password":
+		ret := &SecretSettings{Kind: SecretKindPassword, Min: 12, Max: 16}
+		classes := []string{"lower", "upper", "digit"}
+		if err := parseInt("min", &ret.Min, 1, SECRET_MAX_LENGTH); err != nil {
+			return nil, err
+		}
+		if err := parseInt("max", &ret.Max, 1, SECRET_MAX_LENGTH); err != nil {
+			return nil, err
+		}
+		if ret.Min > ret.Max {
+			return nil, fmt.Errorf("invalid secret bounds, min %d is greater than max %d", ret.Min, ret.Max)
+		}
+		if v, ok := opts["classes"]; ok {
+			classes = []string{}
+			for _, class := range strings.Split(v, "|") {
+				class = strings.ToLower(strings.TrimSpace(class))
+				if _, ok := SecretClasses[class]; !ok {
+					return nil, fmt.Errorf("unknown secret class '%s', expected one of lower, upper, digit or symbol", class)
+				}
+				if !slices.Contains(classes, class) {
+					classes = append(classes, class)
+				}
+			}
+		}
+		if len(classes) > ret.Min {
+			return nil, fmt.Errorf("invalid secret min %d, cannot hold the %d required classes", ret.Min, len(classes))
+		}
+		_, noAmbiguous := opts["no_ambiguous"]
+		for _, class := range classes {
+			chars := SecretClasses[class]
+			if noAmbiguous {
+				chars = strings.Map(func(r rune) rune {
+					if strings.ContainsRune(SecretAmbiguous, r) {
+						return -1
+					}
+					return r
+				}, chars)
+			}
+			ret.Classes = append(ret.Classes, chars)
+			ret.Charset = append(ret.Charset, []rune(chars)...)
+		}
+		if v, ok := opts["min_entropy"]; ok {
+			bits, err := strconv.ParseFloat(v, 64)
+			if err != nil || bits < 0 {
+				return nil, fmt.Errorf("invalid secret min_entropy '%s', expected a number of bits", v)
+			}
+			if entropy := ret.PasswordEntropy(ret.Min); entropy < bits {
+				return nil, fmt.Errorf("invalid secret policy, passwords of %d characters only hold %.1f bits of entropy, below min_entropy %s", ret.Min, entropy, v)
+			}
+		}
+		return ret, nil
+	case "token":
+		ret := &SecretSettings{Kind: SecretKindToken, Encoding: "base64url", Bytes: 32, Prefix: opts["prefix"]}
+		if v, ok := opts["encoding"]; ok {
+			ret.Encoding = strings.ToLower(v)
+			if _, ok := secretEncodings[ret.Encoding]; !ok {
+				return nil, fmt.Errorf("unknown secret encoding '%s', expected one of hex, base32 or base64url", v)
+			}
+		}
+		if err := parseInt("bytes", &ret.Bytes, 1, SECRET_MAX_TOKEN_BYTES); err != nil {
+			return nil, err
+		}
+		return ret, nil
+	}
+	return nil, fmt.Errorf("invalid secret kind '%s', expected 'password' or 'token'", spec)
+}
+
+// PasswordEntropy computes, by inclusion-exclusion, the number of bits needed
+// to pick uniformly a password of n characters holding every required class.
+func (s *SecretSettings) PasswordEntropy(n int) float64 {
+	count := 0.0
+	for mask := range 1 << len(s.Classes) {
+		excluded, sign := 0, 1.0
+		for i, class := range s.Classes {
+			if mask&(1<<i) != 0 {
+				excluded += len([]rune(class))
+				sign = -sign
+			}
+		}
+		count += sign * math.Pow(float64(len(s.Charset)-excluded), float64(n))
+	}
+	return math.Log2(count)
+}
+
+// Entropy returns the estimated entropy in bits of the shortest and longest
+// secrets.
+func (s *SecretSettings) Entropy() (float64, float64) {
+	if s.Kind == SecretKindToken {
+		return float64(s.Bytes * 8), float64(s.Bytes * 8)
+	}
+	return s.PasswordEntropy(s.Min), s.PasswordEntropy(s.Max)
+}
+
+// Rand returns a password drawn uniformly among those holding every required
+// class, or an encoded token.
+func (s *SecretSettings) Rand(rng *rand.Rand) string {
+	if s.Kind == SecretKindToken {
+		buf := make([]byte, s.Bytes)
+		for i := range buf {
+			buf[i] = byte(rng.IntN(256))
+		}
+		return s.Prefix + secretEncodings[s.Encoding](buf)
+	}
+	n := s.Min + rng.IntN(s.Max-s.Min+1)
+	password := make([]rune, n)
+	for {
+		for i := range password {
+			password[i] = s.Charset[rng.IntN(len(s.Charset))]
+		}
+		if !slices.ContainsFunc(s.Classes, func(class string) bool {
+			return !strings.ContainsAny(string(password), class)
+		}) {
+			return string(password)
+		}
+	}
+}
+
+type SecretGenerator struct {
+	*CacheGenerator
+
+	settings *SecretSettings
+	logOnce  sync.Once
+}
+
+func NewSecretGenerator(options *generator.GeneratorOptions, settings *SecretSettings) *SecretGenerator {
+	ret := &SecretGenerator{
+		settings: settings,
+	}
+	ret.CacheGenerator = NewCacheGenerator(options, SECRET_GENERATOR_NAME, func() (string, error) {
+		ret.logOnce.Do(func() {
+			lo, hi := settings.Entropy()
+			slog.Debug("Secret entropy estimate", "generator", ret.GetName(), "min_bits", fmt.Sprintf("%.1f", lo), "max_bits", fmt.Sprintf("%.1f", hi))
+		})
+		return settings.Rand(options.Rand), nil
+	})
+	return ret
+}
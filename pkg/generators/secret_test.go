@@ -0,0 +1,64 @@
+package generators_test
+
+import (
+	"encoding/hex"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestSecretPassword(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	settings, err := generators.ParseSecret("secret", "password;min=8;max=10;classes=lower|digit|symbol;no_ambiguous")
+	if err != nil {
+		t.Fatalf("failed to parse secret settings, %s", err)
+	}
+	for range 500 {
+		password := settings.Rand(src)
+		if n := len(password); n < 8 || n > 10 {
+			t.Fatalf("invalid password length %d in '%s'", n, password)
+		}
+		if strings.ContainsAny(password, generators.SecretAmbiguous+"ABCXYZ") {
+			t.Fatalf("unexpected character in password '%s'", password)
+		}
+		for _, class := range settings.Classes {
+			if !strings.ContainsAny(password, class) {
+				t.Fatalf("password '%s' lacks a character of '%s'", password, class)
+			}
+		}
+	}
+}
+
+func TestSecretEntropy(t *testing.T) {
+	settings, err := generators.ParseSecret("secret", "password;min=2;max=2;classes=digit")
+	if err != nil {
+		t.Fatalf("failed to parse secret settings, %s", err)
+	}
+	if lo, _ := settings.Entropy(); math.Abs(lo-math.Log2(100)) > 1e-9 {
+		t.Errorf("expected %f bits, got %f", math.Log2(100), lo)
+	}
+	// 2 characters among lower and digit, one of each: 2 * 26 * 10
+	if settings, _ = generators.ParseSecret("secret", "password;min=2;max=2;classes=lower|digit"); math.Abs(settings.PasswordEntropy(2)-math.Log2(520)) > 1e-9 {
+		t.Errorf("expected %f bits, got %f", math.Log2(520), settings.PasswordEntropy(2))
+	}
+	for _, tpl := range []string{"password;min=8;min_entropy=128", "password;min=2;classes=lower|upper|digit", "password;classes=emoji", "password;min=9;max=8", "token;encoding=base58", "key"} {
+		if _, err := generators.ParseSecret("secret", tpl); err == nil {
+			t.Errorf("expected an error for secret template '%s'", tpl)
+		}
+	}
+}
+
+func TestSecretToken(t *testing.T) {
+	src := rand.New(rand.NewPCG(1, 2))
+	settings, err := generators.ParseSecret("secret", "token;encoding=hex;bytes=16;prefix=sk_")
+	if err != nil {
+		t.Fatalf("failed to parse secret settings, %s", err)
+	}
+	token := settings.Rand(src)
+	if raw, err := hex.DecodeString(strings.TrimPrefix(token, "sk_")); !strings.HasPrefix(token, "sk_") || err != nil || len(raw) != 16 {
+		t.Errorf("invalid token '%s'", token)
+	}
+}
@@ -0,0 +1,52 @@
+package generators_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestRandSeed(t *testing.T) {
+	draw := func(seed uint64, stream string) []string {
+		options := generator.NewGeneratorOptions()
+		options.SetRandSeed(seed)
+		options = options.Fork(stream)
+		cases := []struct {
+			alloc  generators.GeneratorAllocator
+			params []any
+		}{
+			{generators.AllocateGeneratorIntRange, []any{"int_range", "0..1000000~normal(500000,1000)"}},
+			{generators.AllocateGeneratorUUID, []any{"uuid", "v7"}},
+			{generators.AllocateGeneratorSecret, []any{"secret", "password"}},
+			{generators.AllocateGeneratorDatetime, []any{"datetime", "-1y..now;layout=rfc3339"}},
+			{generators.AllocateGeneratorRegex, []any{"regex", `[a-z]{3,8}@(foo|bar)\.io`}},
+		}
+		values := []string{}
+		for _, c := range cases {
+			g, err := c.alloc(options, c.params...)
+			if err != nil {
+				t.Fatalf("failed to allocate %v, %s", c.params, err)
+			}
+			for range 5 {
+				v, err := g.Next()
+				if err != nil {
+					t.Fatalf("failed to generate %v, %s", c.params, err)
+				}
+				values = append(values, v)
+			}
+		}
+		return values
+	}
+	values := draw(42, "person.age#0")
+	if again := draw(42, "person.age#0"); !slices.Equal(values, again) {
+		t.Errorf("expected identical values with the same seed, got %v and %v", values, again)
+	}
+	if other := draw(42, "person.age#1"); slices.Equal(values, other) {
+		t.Errorf("expected streams to differ, got %v twice", values)
+	}
+	if other := draw(43, "person.age#0"); slices.Equal(values, other) {
+		t.Errorf("expected seeds to differ, got %v twice", values)
+	}
+}
@@ -0,0 +1,107 @@
+package generators
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const SEQUENCE_GENERATOR_NAME = "sequence"
+
+var SequenceOptions = []string{"start", "step", "limit", "wrap"}
+
+// Sequence describes monotonic values start, start+step, ... up to an
+// optional limit, after which it either stops or wraps back to start.
+type Sequence struct {
+	Format string
+	Start  int64
+	Step   int64
+	Limit  *int64
+	Wrap   bool
+}
+
+// Len returns the number of values before reaching the limit, or -1.
+func (s *Sequence) Len() int64 {
+	if s.Limit == nil {
+		return -1
+	}
+	return (*s.Limit-s.Start)/s.Step + 1
+}
+
+// At returns the formatted i-th value of the sequence.
+func (s *Sequence) At(i int64) (string, error) {
+	if n := s.Len(); n != -1 && i >= n {
+		if !s.Wrap {
+			return "", fmt.Errorf("sequence exhausted after %d values", n)
+		}
+		i %= n
+	}
+	return fmt.Sprintf(s.Format, s.Start+i*s.Step), nil
+}
+
+// ParseSequence parses 'format;start=1;step=1;limit=N;wrap', format being a
+// printf verb such as 'INV-%06d'.
+func ParseSequence(params ...any) (*Sequence, error) {
+	spec, opts, err := ParseSpecOptions(SequenceOptions, params...)
+	if err != nil {
+		return nil, err
+	}
+	ret := &Sequence{Format: spec, Start: 1, Step: 1}
+	if len(ret.Format) == 0 {
+		ret.Format = "%d"
+	}
+	if out := fmt.Sprintf(ret.Format, int64(0)); strings.Contains(out, "%!") {
+		return nil, fmt.Errorf("invalid sequence format '%s', expected a single integer verb like '%%06d'", ret.Format)
+	}
+	parseInt := func(key string, dst *int64) error {
+		v, ok := opts[key]
+		if !ok {
+			return nil
+		}
+		if *dst, err = strconv.ParseInt(v, 10, 64); err != nil {
+			return fmt.Errorf("invalid sequence %s '%s', %s", key, v, err)
+		}
+		return nil
+	}
+	if err = parseInt("start", &ret.Start); err != nil {
+		return nil, err
+	}
+	if err = parseInt("step", &ret.Step); err != nil {
+		return nil, err
+	}
+	if ret.Step == 0 {
+		return nil, fmt.Errorf("invalid sequence step, it cannot be 0")
+	}
+	if _, ok := opts["limit"]; ok {
+		ret.Limit = new(int64)
+		if err = parseInt("limit", ret.Limit); err != nil {
+			return nil, err
+		}
+		if (ret.Step > 0 && *ret.Limit < ret.Start) || (ret.Step < 0 && *ret.Limit > ret.Start) {
+			return nil, fmt.Errorf("invalid sequence limit %d, unreachable from %d with step %d", *ret.Limit, ret.Start, ret.Step)
+		}
+	}
+	_, ret.Wrap = opts["wrap"]
+	if ret.Wrap && ret.Limit == nil {
+		return nil, fmt.Errorf("invalid sequence, wrap requires a limit")
+	}
+	return ret, nil
+}
+
+type SequenceGenerator struct {
+	*CacheGenerator
+
+	sequence *Sequence
+	index    atomic.Int64
+}
+
+func NewSequenceGenerator(options *generator.GeneratorOptions, sequence *Sequence) *SequenceGenerator {
+	ret := &SequenceGenerator{sequence: sequence}
+	ret.CacheGenerator = NewCacheGenerator(options, SEQUENCE_GENERATOR_NAME, func() (string, error) {
+		return ret.sequence.At(ret.index.Add(1) - 1)
+	})
+	return ret
+}
@@ -0,0 +1,57 @@
+package generators_test
+
+import (
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestSequenceGenerator(t *testing.T) {
+	seq, err := generators.ParseSequence("sequence", "INV-%06d;start=1000;step=5;limit=1010;wrap")
+	if err != nil {
+		t.Fatalf("failed to parse sequence, %s", err)
+	}
+	g := generators.NewSequenceGenerator(generator.NewGeneratorOptions(), seq)
+	got := []string{}
+	for range 4 {
+		v, err := g.Next()
+		if err != nil {
+			t.Fatalf("failed to generate sequence value, %s", err)
+		}
+		got = append(got, v)
+	}
+	expected := []string{"INV-001000", "INV-001005", "INV-001010", "INV-001000"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("invalid sequence, expected %v but got %v", expected, got)
+	}
+}
+
+func TestSequenceGeneratorConcurrent(t *testing.T) {
+	seq, err := generators.ParseSequence("sequence")
+	if err != nil {
+		t.Fatalf("failed to parse sequence, %s", err)
+	}
+	g := generators.NewSequenceGenerator(generator.NewGeneratorOptions(), seq)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	seen := map[string]bool{}
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				v, _ := g.Next()
+				mu.Lock()
+				seen[v] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(seen) != 800 {
+		t.Errorf("expected 800 distinct values but got %d", len(seen))
+	}
+}
@@ -0,0 +1,191 @@
+package generators
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const TEMPLATE_GENERATOR_NAME = "template"
+
+// Validator is implemented by generators referencing other resources, it is
+// called once every resource has been loaded.
+type Validator interface {
+	Validate() error
+}
+
+// Referencer is implemented by generators drawing from other resources.
+type Referencer interface {
+	References() []string
+}
+
+// CheckReferenceCycles walks the resources reachable from refs, reporting the
+// first one that ends up referencing itself, which would otherwise overflow
+// the stack at generation time.
+func CheckReferenceCycles(refs []string, resGetter func(name string) generator.Generator) error {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := map[string]int{}
+	path := []string{}
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			cycle := append(path[slices.Index(path, name):], name)
+			return fmt.Errorf("cycle between resources %s", strings.Join(cycle, " -> "))
+		}
+		state[name] = visiting
+		path = append(path, name)
+		if r, ok := resGetter(name).(Referencer); ok {
+			for _, ref := range r.References() {
+				if err := visit(ref); err != nil {
+					return err
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = visited
+		return nil
+	}
+	for _, ref := range refs {
+		if err := visit(ref); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TemplatePart is either a literal string or a reference to a resource.
+type TemplatePart struct {
+	Value       string
+	IsReference bool
+}
+
+type TemplateGenerator struct {
+	*CacheGenerator
+
+	template  string
+	parts     []TemplatePart
+	resGetter func(name string) generator.Generator
+}
+
+// ParseTemplate splits a template such as '{person.firstName} \{literal\}' into
+// its parts. Braces and backslashes can be escaped with a backslash.
+func ParseTemplate(params ...any) (template string, parts []TemplatePart, err error) {
+	if len(params) < 2 {
+		return "", nil, fmt.Errorf("invalid arguments, expected ['generator_name', 'template'] but got %v", params)
+	}
+	args, err := ParseStrings(len(params), params...)
+	if err != nil {
+		return "", nil, err
+	}
+	// templates may legitimately contain the ':' argument separator
+	template = strings.Join(args[1:], ":")
+	parts = []TemplatePart{}
+	accu := strings.Builder{}
+	inRef := false
+	escaped := false
+	flush := func(isRef bool) {
+		if accu.Len() > 0 || isRef {
+			parts = append(parts, TemplatePart{Value: accu.String(), IsReference: isRef})
+		}
+		accu.Reset()
+	}
+	for pos, ch := range template {
+		switch {
+		case escaped:
+			accu.WriteRune(ch)
+			escaped = false
+		case ch == '\\':
+			escaped = true
+		case ch == '{':
+			if inRef {
+				return "", nil, fmt.Errorf("invalid template '%s', nested '{' at offset %d", template, pos)
+			}
+			flush(false)
+			inRef = true
+		case ch == '}':
+			if !inRef {
+				return "", nil, fmt.Errorf("invalid template '%s', unexpected '}' at offset %d", template, pos)
+			}
+			name := strings.TrimSpace(accu.String())
+			if len(name) == 0 {
+				return "", nil, fmt.Errorf("invalid template '%s', empty reference at offset %d", template, pos)
+			}
+			accu.Reset()
+			accu.WriteString(name)
+			flush(true)
+			inRef = false
+		default:
+			accu.WriteRune(ch)
+		}
+	}
+	if escaped {
+		return "", nil, fmt.Errorf("invalid template '%s', dangling escape character", template)
+	}
+	if inRef {
+		return "", nil, fmt.Errorf("invalid template '%s', unterminated reference", template)
+	}
+	flush(false)
+	return template, parts, nil
+}
+
+func NewTemplateGenerator(options *generator.GeneratorOptions, template string, parts []TemplatePart, resGetter func(name string) generator.Generator) *TemplateGenerator {
+	ret := &TemplateGenerator{
+		template:  template,
+		parts:     parts,
+		resGetter: resGetter,
+	}
+	ret.CacheGenerator = NewCacheGenerator(options, TEMPLATE_GENERATOR_NAME, ret.next)
+	return ret
+}
+
+func (g *TemplateGenerator) Validate() error {
+	for _, part := range g.parts {
+		if part.IsReference && g.resGetter(part.Value) == nil {
+			return fmt.Errorf("unknown resource '%s' in template '%s'", part.Value, g.template)
+		}
+	}
+	return CheckReferenceCycles(g.References(), g.resGetter)
+}
+
+func (g *TemplateGenerator) References() []string {
+	ret := []string{}
+	for _, part := range g.parts {
+		if part.IsReference {
+			ret = append(ret, part.Value)
+		}
+	}
+	return ret
+}
+
+func (g *TemplateGenerator) next() (string, error) {
+	buf := strings.Builder{}
+	for _, part := range g.parts {
+		if !part.IsReference {
+			buf.WriteString(part.Value)
+			continue
+		}
+		gen := g.resGetter(part.Value)
+		if gen == nil {
+			return "", fmt.Errorf("unknown resource '%s' in template '%s'", part.Value, g.template)
+		}
+		value, err := SampleValue(gen)
+		if err != nil {
+			return "", err
+		}
+		// a missing part makes the whole value missing
+		if generator.IsNull(value) {
+			return generator.NULL_VALUE, nil
+		}
+		buf.WriteString(value)
+	}
+	return buf.String(), nil
+}
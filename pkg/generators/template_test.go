@@ -0,0 +1,111 @@
+package generators_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestParseTemplate(t *testing.T) {
+	_, parts, err := generators.ParseTemplate("template", `{person.firstName} \{{ person.age }\}`)
+	if err != nil {
+		t.Fatalf("failed to parse template, %s", err)
+	}
+	expected := []generators.TemplatePart{
+		{Value: "person.firstName", IsReference: true},
+		{Value: " {"},
+		{Value: "person.age", IsReference: true},
+		{Value: "}"},
+	}
+	if !slices.Equal(parts, expected) {
+		t.Errorf("invalid template parts, expected %v but got %v", expected, parts)
+	}
+}
+
+func TestParseTemplateWithSeparator(t *testing.T) {
+	tpl, _, err := generators.ParseTemplate("template", "{a}", " {b}")
+	if err != nil {
+		t.Fatalf("failed to parse template, %s", err)
+	}
+	if tpl != "{a}: {b}" {
+		t.Errorf("invalid template, expected '{a}: {b}' but got '%s'", tpl)
+	}
+}
+
+func TestParseTemplateErrors(t *testing.T) {
+	for _, tpl := range []string{"{unterminated", "stray}", "{}", `trailing\`, "{a{b}}"} {
+		if _, _, err := generators.ParseTemplate("template", tpl); err == nil {
+			t.Errorf("expected an error for template '%s'", tpl)
+		}
+	}
+}
+
+func TestTemplateGeneratorNulls(t *testing.T) {
+	options := generator.NewGeneratorOptions()
+	var resources map[string]generator.Generator
+	resGetter := func(name string) generator.Generator {
+		return resources[name]
+	}
+	resources = map[string]generator.Generator{
+		"id":       generators.NewSequenceGenerator(options, mustParseSequence(t, "%d")),
+		"missing":  generators.NewNullableGenerator(options, "id", 1, resGetter),
+		"optional": generators.NewNullableGenerator(options, "id", 0, resGetter),
+	}
+	for _, c := range [][2]string{
+		{"#{id} {optional}", "#1 2"},
+		{"#{id} {missing}", generator.NULL_VALUE},
+		{"{missing}", generator.NULL_VALUE},
+	} {
+		tpl, expected := c[0], c[1]
+		template, parts, err := generators.ParseTemplate("template", tpl)
+		if err != nil {
+			t.Fatalf("failed to parse template '%s', %s", tpl, err)
+		}
+		v, err := generators.NewTemplateGenerator(options, template, parts, resGetter).Next()
+		if err != nil {
+			t.Fatalf("failed to generate template '%s', %s", tpl, err)
+		}
+		if v != expected {
+			t.Errorf("expected template '%s' to yield %q but got %q", tpl, expected, v)
+		}
+	}
+}
+
+func TestTemplateGeneratorCycles(t *testing.T) {
+	options := generator.NewGeneratorOptions()
+	var resources map[string]generator.Generator
+	resGetter := func(name string) generator.Generator {
+		return resources[name]
+	}
+	template := func(tpl string) generator.Generator {
+		tpl, parts, err := generators.ParseTemplate("template", tpl)
+		if err != nil {
+			t.Fatalf("failed to parse template, %s", err)
+		}
+		return generators.NewTemplateGenerator(options, tpl, parts, resGetter)
+	}
+	resources = map[string]generator.Generator{
+		"id":      generators.NewSequenceGenerator(options, mustParseSequence(t, "%d")),
+		"self":    template("{self}!"),
+		"a":       template("a{b}"),
+		"b":       generators.NewUnionGenerator(nil, options, []generators.UnionVariant{{Name: "id", Weight: 1}, {Name: "c", Weight: 1}}, resGetter),
+		"c":       generators.NewNullableGenerator(options, "a", 0.5, resGetter),
+		"reuse":   template("{id}-{id}|{pair}"),
+		"pair":    template("{id}{id}"),
+		"nocycle": template("{reuse}{pair}"),
+	}
+	for name, expected := range map[string]string{
+		"self": "cycle between resources self -> self",
+		"a":    "cycle between resources b -> c -> a -> b",
+	} {
+		err := resources[name].(generators.Validator).Validate()
+		if err == nil || err.Error() != expected {
+			t.Errorf("expected '%s' to report '%s' but got %v", name, expected, err)
+		}
+	}
+	if err := resources["nocycle"].(generators.Validator).Validate(); err != nil {
+		t.Errorf("expected resources referenced twice to be valid but got %s", err)
+	}
+}
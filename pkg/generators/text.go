@@ -0,0 +1,220 @@
+package generators
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/welschmorgan/datagen/pkg/generator"
+)
+
+const TEXT_GENERATOR_NAME = "text"
+
+const TEXT_WORD_TABLE = "misc_prop"
+const TEXT_WORD_TYPE = "word"
+
+var TextOptions = []string{"min", "max", "max_chars", "locale"}
+
+type TextUnit int64
+
+const (
+	TextUnitUnknown TextUnit = iota
+	TextUnitWords
+	TextUnitSentence
+	TextUnitParagraph
+	TextUnitMax
+)
+
+func (u TextUnit) String() string {
+	switch u {
+	case TextUnitWords:
+		return "words"
+	case TextUnitSentence:
+		return "sentence"
+	case TextUnitParagraph:
+		return "paragraph"
+	}
+	return "unknown"
+}
+
+// bounds returns the default number of sub-units: words per group of words
+// or sentence, sentences per paragraph.
+func (u TextUnit) bounds() (int, int) {
+	switch u {
+	case TextUnitWords:
+		return 1, 5
+	case TextUnitSentence:
+		return 4, 14
+	case TextUnitParagraph:
+		return 3, 7
+	}
+	return 0, 0
+}
+
+type TextSettings struct {
+	Unit     TextUnit
+	Min      int
+	Max      int
+	MaxChars int
+	Locale   string
+}
+
+// ParseText parses 'unit;min=N;max=N;max_chars=N;locale=fr-FR' where unit is
+// one of 'words', 'sentence' or 'paragraph', singular or plural.
+func ParseText(params ...any) (*TextSettings, error) {
+	spec, opts, err := ParseSpecOptions(TextOptions, params...)
+	if err != nil {
+		return nil, err
+	}
+	ret := &TextSettings{Locale: opts["locale"]}
+	for u := range TextUnitMax {
+		if strings.EqualFold(strings.TrimSuffix(u.String(), "s"), strings.TrimSuffix(spec, "s")) {
+			ret.Unit = u
+		}
+	}
+	if ret.Unit == TextUnitUnknown {
+		return nil, fmt.Errorf("invalid text unit '%s', expected one of words, sentence or paragraph", spec)
+	}
+	ret.Min, ret.Max = ret.Unit.bounds()
+	for k, dst := range map[string]*int{"min": &ret.Min, "max": &ret.Max, "max_chars": &ret.MaxChars} {
+		if v, ok := opts[k]; ok {
+			if *dst, err = strconv.Atoi(v); err != nil || *dst < 1 {
+				return nil, fmt.Errorf("invalid text %s '%s', expected a positive number", k, v)
+			}
+		}
+	}
+	if ret.Min > ret.Max {
+		return nil, fmt.Errorf("invalid text bounds, min %d is greater than max %d", ret.Min, ret.Max)
+	}
+	return ret, nil
+}
+
+type TextGenerator struct {
+	*CacheGenerator
+
+	db       *sql.DB
+	settings *TextSettings
+
+	once       sync.Once
+	loadErr    error
+	vocabulary []string
+}
+
+func NewTextGenerator(options *generator.GeneratorOptions, db *sql.DB, settings *TextSettings) *TextGenerator {
+	ret := &TextGenerator{
+		db:       db,
+		settings: settings,
+	}
+	ret.CacheGenerator = NewCacheGenerator(options, TEXT_GENERATOR_NAME, ret.next)
+	return ret
+}
+
+func (g *TextGenerator) load() error {
+	rawQuery := fmt.Sprintf("SELECT p.value FROM %s p WHERE p.type = ? ORDER BY p.id", TEXT_WORD_TABLE)
+	params := []any{TEXT_WORD_TYPE}
+	if len(g.settings.Locale) > 0 {
+		rawQuery = fmt.Sprintf("SELECT p.value FROM %s p JOIN locale l ON l.id = p.locale_id WHERE p.type = ? AND l.name = ? ORDER BY p.id", TEXT_WORD_TABLE)
+		params = append(params, g.settings.Locale)
+	}
+	rows, err := g.db.Query(rawQuery, params...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return fmt.Errorf("failed to scan rows: %s", err)
+		}
+		if isWord(value) {
+			g.vocabulary = append(g.vocabulary, value)
+		}
+	}
+	if len(g.vocabulary) == 0 {
+		return fmt.Errorf("invalid text generator, no word found: '%s' (params=%v)", rawQuery, params)
+	}
+	return nil
+}
+
+// isWord skips corpus entries made of digits or punctuation.
+func isWord(s string) bool {
+	for _, r := range s {
+		if !unicode.IsLetter(r) && r != '-' && r != '\'' {
+			return false
+		}
+	}
+	return len(s) > 0
+}
+
+func (g *TextGenerator) next() (string, error) {
+	g.once.Do(func() {
+		g.loadErr = g.load()
+	})
+	if g.loadErr != nil {
+		return "", g.loadErr
+	}
+	n := g.settings.Min + g.options.Rand.IntN(g.settings.Max-g.settings.Min+1)
+	var ret string
+	switch g.settings.Unit {
+	case TextUnitWords:
+		ret = g.words(n)
+	case TextUnitSentence:
+		ret = g.sentence(n)
+	case TextUnitParagraph:
+		sentences := []string{}
+		min, max := TextUnitSentence.bounds()
+		for range n {
+			sentences = append(sentences, g.sentence(min+g.options.Rand.IntN(max-min+1)))
+		}
+		ret = strings.Join(sentences, " ")
+	}
+	return TruncateWords(ret, g.settings.MaxChars), nil
+}
+
+func (g *TextGenerator) word() string {
+	return g.vocabulary[g.options.Rand.IntN(len(g.vocabulary))]
+}
+
+func (g *TextGenerator) words(n int) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = g.word()
+	}
+	return strings.Join(words, " ")
+}
+
+// sentence capitalizes n words, sometimes adding a comma, ending with a dot.
+func (g *TextGenerator) sentence(n int) string {
+	buf := strings.Builder{}
+	for i := range n {
+		word := g.word()
+		if i == 0 {
+			r, size := utf8.DecodeRuneInString(word)
+			word = string(unicode.ToUpper(r)) + word[size:]
+		} else {
+			if i > 2 && i < n-2 && g.options.Rand.IntN(8) == 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(" ")
+		}
+		buf.WriteString(word)
+	}
+	buf.WriteString(".")
+	return buf.String()
+}
+
+// TruncateWords cuts s at the last word boundary fitting in maxChars runes.
+func TruncateWords(s string, maxChars int) string {
+	if maxChars <= 0 || utf8.RuneCountInString(s) <= maxChars {
+		return s
+	}
+	runes := []rune(s)[:maxChars]
+	if pos := strings.LastIndex(string(runes), " "); pos > 0 {
+		return strings.TrimRight(string(runes)[:pos], ",")
+	}
+	return string(runes)
+}
@@ -0,0 +1,140 @@
+package generators_test
+
+import (
+	"database/sql"
+	"math/rand/v2"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/welschmorgan/datagen/pkg/generator"
+	"github.com/welschmorgan/datagen/pkg/generators"
+)
+
+func TestParseText(t *testing.T) {
+	settings, err := generators.ParseText("text", "sentences;min=2;max=4;max_chars=80;locale=es-ES")
+	if err != nil {
+		t.Fatalf("failed to parse text settings, %s", err)
+	}
+	if settings.Unit != generators.TextUnitSentence || settings.Min != 2 || settings.Max != 4 || settings.MaxChars != 80 || settings.Locale != "es-ES" {
+		t.Errorf("invalid text settings %+v", settings)
+	}
+	for _, tpl := range []string{"chapter", "words;min=5;max=2", "words;max=0", "words;unknown=1"} {
+		if _, err := generators.ParseText("text", tpl); err == nil {
+			t.Errorf("expected an error for text template '%s'", tpl)
+		}
+	}
+}
+
+// textDB returns a DB holding a few words for two locales.
+func textDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "text.db"))
+	if err != nil {
+		t.Fatalf("failed to open DB, %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`
+		CREATE TABLE locale (id INTEGER PRIMARY KEY, name TEXT);
+		CREATE TABLE misc_prop (id INTEGER PRIMARY KEY, locale_id INTEGER, type TEXT, value TEXT);
+		INSERT INTO locale VALUES (1, 'fr-FR'), (2, 'es-ES');
+		INSERT INTO misc_prop (locale_id, type, value) VALUES
+			(1, 'word', 'le'), (1, 'word', 'chat'), (1, 'word', 'dort'), (1, 'word', 'de'), (1, 'word', '42'),
+			(2, 'word', 'el'), (2, 'word', 'perro'), (2, 'word', 'come'), (2, 'word', 'de'), (2, 'word', 'que');`)
+	if err != nil {
+		t.Fatalf("failed to create text corpus, %s", err)
+	}
+	return db
+}
+
+func TestTextGenerator(t *testing.T) {
+	db := textDB(t)
+	vocabulary := map[string]bool{"el": true, "perro": true, "come": true, "de": true, "que": true}
+	sentence := regexp.MustCompile(`^\p{Lu}\p{Ll}*(,? \p{Ll}+)*\.$`)
+	options := generator.NewGeneratorOptions()
+	options.Rand = rand.New(rand.NewPCG(1, 2))
+	for _, c := range []struct {
+		tpl      string
+		min, max int
+	}{
+		{"words;min=2;max=4;locale=es-ES", 2, 4},
+		{"sentence;min=3;max=6;locale=es-ES", 3, 6},
+		{"paragraph;min=2;max=3;locale=es-ES", 2, 3},
+	} {
+		settings, err := generators.ParseText("text", c.tpl)
+		if err != nil {
+			t.Fatalf("failed to parse text settings '%s', %s", c.tpl, err)
+		}
+		g := generators.NewTextGenerator(options, db, settings)
+		for range 50 {
+			v, err := g.Next()
+			if err != nil {
+				t.Fatalf("failed to generate text '%s', %s", c.tpl, err)
+			}
+			var n int
+			switch settings.Unit {
+			case generators.TextUnitWords:
+				n = len(strings.Fields(v))
+			case generators.TextUnitSentence:
+				if !sentence.MatchString(v) {
+					t.Errorf("invalid sentence '%s'", v)
+				}
+				n = len(strings.Fields(v))
+			case generators.TextUnitParagraph:
+				sentences := strings.SplitAfter(v, ". ")
+				for _, s := range sentences {
+					if !sentence.MatchString(strings.TrimSpace(s)) {
+						t.Errorf("invalid sentence '%s' in paragraph '%s'", s, v)
+					}
+				}
+				n = len(sentences)
+			}
+			if n < c.min || n > c.max {
+				t.Errorf("expected %d to %d units in '%s' but got %d", c.min, c.max, v, n)
+			}
+			for _, w := range strings.Fields(strings.ToLower(v)) {
+				if w = strings.TrimRight(w, ",."); !vocabulary[w] {
+					t.Errorf("unexpected word '%s' in '%s', expected es-ES words only", w, v)
+				}
+			}
+		}
+	}
+}
+
+func TestTextGeneratorMaxChars(t *testing.T) {
+	settings, err := generators.ParseText("text", "paragraph;max_chars=40;locale=fr-FR")
+	if err != nil {
+		t.Fatalf("failed to parse text settings, %s", err)
+	}
+	g := generators.NewTextGenerator(generator.NewGeneratorOptions(), textDB(t), settings)
+	for range 20 {
+		v, err := g.Next()
+		if err != nil {
+			t.Fatalf("failed to generate text, %s", err)
+		}
+		if utf8.RuneCountInString(v) > 40 || strings.HasSuffix(v, " ") || strings.HasSuffix(v, ",") {
+			t.Errorf("invalid truncated text '%s'", v)
+		}
+	}
+}
+
+func TestTruncateWords(t *testing.T) {
+	for _, c := range []struct {
+		input    string
+		max      int
+		expected string
+	}{
+		{"Le chat dort.", 0, "Le chat dort."},
+		{"Le chat dort.", 13, "Le chat dort."},
+		{"Le chat dort.", 10, "Le chat"},
+		{"Le chat, dort.", 10, "Le chat"},
+		{"Él come", 4, "Él"},
+		{"anticonstitutionnellement", 5, "antic"},
+	} {
+		if got := generators.TruncateWords(c.input, c.max); got != c.expected {
+			t.Errorf("expected '%s' truncated to %d chars to be '%s' but got '%s'", c.input, c.max, c.expected, got)
+		}
+	}
+}
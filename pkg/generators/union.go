@@ -2,7 +2,7 @@ package generators
 
 import (
 	"database/sql"
-	"math/rand/v2"
+	"fmt"
 
 	"github.com/welschmorgan/datagen/pkg/generator"
 )
@@ -12,16 +12,50 @@ const UNION_GENERATOR_NAME = "union"
 type UnionGenerator struct {
 	*CacheGenerator
 
-	union []string
+	union         []UnionVariant
+	totalWeight   int64
+	variantGetter func(name string) generator.Generator
 }
 
-func NewUnionGenerator(db *sql.DB, options *generator.GeneratorOptions, union []string, variantGetter func(name string) generator.Generator) *UnionGenerator {
-	return &UnionGenerator{
-		CacheGenerator: NewCacheGenerator(options, UNION_GENERATOR_NAME, func() (string, error) {
-			variantId := rand.IntN(len(union))
-			variant := union[variantId]
-			return variantGetter(variant).Next()
-		}),
-		union: union,
+func NewUnionGenerator(db *sql.DB, options *generator.GeneratorOptions, union []UnionVariant, variantGetter func(name string) generator.Generator) *UnionGenerator {
+	ret := &UnionGenerator{
+		union:         union,
+		variantGetter: variantGetter,
 	}
+	for _, variant := range union {
+		ret.totalWeight += variant.Weight
+	}
+	ret.CacheGenerator = NewCacheGenerator(options, UNION_GENERATOR_NAME, func() (string, error) {
+		variant := ret.pick(options.Rand.Int64N(ret.totalWeight))
+		return variantGetter(variant.Name).Next()
+	})
+	return ret
+}
+
+func (g *UnionGenerator) Validate() error {
+	for _, variant := range g.union {
+		if g.variantGetter(variant.Name) == nil {
+			return fmt.Errorf("unknown union variant '%s'", variant.Name)
+		}
+	}
+	return CheckReferenceCycles(g.References(), g.variantGetter)
+}
+
+func (g *UnionGenerator) References() []string {
+	ret := []string{}
+	for _, variant := range g.union {
+		ret = append(ret, variant.Name)
+	}
+	return ret
+}
+
+// pick returns the variant whose cumulative weight range contains n.
+func (g *UnionGenerator) pick(n int64) UnionVariant {
+	for _, variant := range g.union {
+		if n < variant.Weight {
+			return variant
+		}
+		n -= variant.Weight
+	}
+	return g.union[len(g.union)-1]
 }
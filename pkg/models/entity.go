@@ -0,0 +1,48 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const ENTITY_TABLE = "entity"
+
+// Entity is a record schema, Fields listing its named fields such as
+// '{id: id.sequence, firstName: person.firstName}'.
+type Entity struct {
+	Id     int64
+	Name   string
+	Fields string
+}
+
+func NewEntity(id int64, name, fields string) *Entity {
+	return &Entity{
+		Id:     id,
+		Name:   name,
+		Fields: fields,
+	}
+}
+
+func (e *Entity) String() string {
+	return fmt.Sprintf("Entity #%d: %s = %s", e.Id, e.Name, e.Fields)
+}
+
+func LoadEntities(db *sql.DB) ([]*Entity, error) {
+	res, err := db.Query(fmt.Sprintf("SELECT id, name, fields FROM %s ORDER BY id", ENTITY_TABLE))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list table '%s', %s", ENTITY_TABLE, err)
+	}
+	defer res.Close()
+	ret := []*Entity{}
+	rowId := 1
+	for res.Next() {
+		var id int64
+		var name, fields string
+		if err := res.Scan(&id, &name, &fields); err != nil {
+			return nil, fmt.Errorf("failed to read row #%d of %s, %s", rowId, ENTITY_TABLE, err)
+		}
+		ret = append(ret, NewEntity(id, name, fields))
+		rowId += 1
+	}
+	return ret, nil
+}
@@ -15,7 +15,7 @@ func NewLocale(id int64, name string) *Locale {
 }
 
 func LoadLocales(db *sql.DB) ([]*Locale, error) {
-	res, err := db.Query("SELECT * FROM locale")
+	res, err := db.Query("SELECT * FROM locale ORDER BY id")
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+const LOCATION_TABLE = "location_prop"
+
+// Location is a prop of the location table, Code holding the postal code of
+// towns, the ISO 3166 code of countries or the state of 'town-state' props.
+type Location struct {
+	Prop
+	Code *string
+}
+
+func NewLocation(id int64, locale_id int64, typ, value string, code *string) *Location {
+	return &Location{
+		Prop: *NewProp(id, locale_id, typ, value),
+		Code: code,
+	}
+}
+
+func LoadLocations(db *sql.DB, typ string, locale *string) ([]*Location, error) {
+	rawQuery := fmt.Sprintf("SELECT p.id, p.locale_id, p.type, p.value, p.code FROM %s p WHERE p.type = ? ORDER BY p.id", LOCATION_TABLE)
+	params := []interface{}{typ}
+	if locale != nil {
+		rawQuery = fmt.Sprintf("SELECT p.id, p.locale_id, p.type, p.value, p.code FROM %s p JOIN locale l ON l.id = p.locale_id WHERE p.type = ? AND l.name = ? ORDER BY p.id", LOCATION_TABLE)
+		params = append(params, *locale)
+	}
+	res, err := db.Query(rawQuery, params...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list table '%s', %s", LOCATION_TABLE, err)
+	}
+	defer res.Close()
+	ret := []*Location{}
+	rowId := 1
+	for res.Next() {
+		var id, locale_id int64
+		var typ, value string
+		var code *string
+		if err := res.Scan(&id, &locale_id, &typ, &value, &code); err != nil {
+			return nil, fmt.Errorf("failed to read row #%d of %s, %s", rowId, LOCATION_TABLE, err)
+		}
+		ret = append(ret, NewLocation(id, locale_id, typ, value, code))
+		rowId += 1
+	}
+	return ret, nil
+}
@@ -47,6 +47,7 @@ func LoadProps(db *sql.DB, table string, typ *string, value *string) ([]*Prop, e
 		rawQuery = fmt.Sprintf("%s AND value = ?", rawQuery)
 		params = append(params, *value)
 	}
+	rawQuery = fmt.Sprintf("%s ORDER BY id", rawQuery)
 	res, err = db.Query(rawQuery, params...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list table '%s', %s", table, err)
@@ -44,7 +44,7 @@ func (r *Resource) FullGeneratorName() string {
 
 func LoadResources(db *sql.DB) []*Resource {
 	ret := []*Resource{}
-	resources, err := db.Query("select * from resource")
+	resources, err := db.Query("select * from resource order by id")
 	if err != nil {
 		slog.Error("failed to load resources", "err", err)
 		panic("Fatal error")
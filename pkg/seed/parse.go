@@ -159,7 +159,7 @@ func NewParserFromDecl(s string) (Parser, map[ParserArg]string, error) {
 				return nil, nil, fmt.Errorf("unknown parser argument '%s'", parts[0])
 			}
 			if len(parts) > 1 {
-				ret[found] = parts[1]
+				ret[found] = unquote(parts[1])
 			} else {
 				ret[found] = ""
 			}
@@ -177,3 +177,12 @@ func NewParserFromDecl(s string) (Parser, map[ParserArg]string, error) {
 	}
 	return parser, ret, nil
 }
+
+// unquote strips matching surrounding quotes, allowing values such as
+// delim=' ' which would otherwise be trimmed.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
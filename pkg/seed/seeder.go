@@ -38,6 +38,10 @@ func NewSeederFromConfig(db *sql.DB, c *config.Config) (*Seeder, error) {
 		seeds = append(seeds, NewStdSeed(config.SeedTypeSchema, "schema", "resource", "assets/seed.sql", nil, nil, nil, nil, nil, NewQueryUploader(db, *DEFAULT_SEED_SCHEMA)))
 	}
 	charmap := func(name string) (*charmap.Charmap, error) {
+		if len(name) == 0 || strings.EqualFold(name, "utf-8") || strings.EqualFold(name, "utf8") {
+			// already utf-8, no decoding needed
+			return nil, nil
+		}
 		for _, enc := range charmap.All {
 			cm, ok := enc.(*charmap.Charmap)
 			if ok && strings.EqualFold(cm.String(), name) {
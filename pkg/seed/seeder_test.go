@@ -0,0 +1,77 @@
+package seed_test
+
+import (
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/welschmorgan/datagen/pkg/models"
+	"github.com/welschmorgan/datagen/pkg/seed"
+)
+
+// seededDB returns a DB seeded with the default schema, and its uploader.
+func seededDB(t *testing.T) (*sql.DB, *seed.QueryUploader) {
+	schema, err := os.ReadFile(filepath.Join("..", "..", "assets", "seed.sql"))
+	if err != nil {
+		t.Fatalf("failed to read seed schema, %s", err)
+	}
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "datagen.db"))
+	if err != nil {
+		t.Fatalf("failed to open DB, %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	uploader := seed.NewQueryUploader(db, string(schema))
+	if err := uploader.Upload(nil); err != nil {
+		t.Fatalf("failed to seed DB, %s", err)
+	}
+	return db, uploader
+}
+
+func TestReseedUpdatesEntities(t *testing.T) {
+	db, uploader := seededDB(t)
+	// entities as seeded by a previous version
+	if _, err := db.Exec("UPDATE entity SET fields = '{id: id.sequence}' WHERE name = 'person'"); err != nil {
+		t.Fatalf("failed to alter entity, %s", err)
+	}
+	if err := uploader.Upload(nil); err != nil {
+		t.Fatalf("failed to reseed DB, %s", err)
+	}
+	entities, err := models.LoadEntities(db)
+	if err != nil {
+		t.Fatalf("failed to load entities, %s", err)
+	}
+	names := []string{}
+	for _, e := range entities {
+		names = append(names, e.Name)
+		if e.Name == "person" && !strings.Contains(e.Fields, "birthDate: birthdate(age)") {
+			t.Errorf("expected reseeding to update entity 'person' but got %s", e.Fields)
+		}
+	}
+	if len(names) != 3 {
+		t.Errorf("expected reseeding not to duplicate entities but got %v", names)
+	}
+}
+
+func TestReseedUpdatesResources(t *testing.T) {
+	db, uploader := seededDB(t)
+	before := len(models.LoadResources(db))
+	// resources as seeded by a previous version
+	if _, err := db.Exec("UPDATE resource SET generator = 'pattern', template = '+33 6XXXXXXXX' WHERE name = 'person.phone.mobile'"); err != nil {
+		t.Fatalf("failed to alter resource, %s", err)
+	}
+	if err := uploader.Upload(nil); err != nil {
+		t.Fatalf("failed to reseed DB, %s", err)
+	}
+	resources := models.LoadResources(db)
+	for _, r := range resources {
+		if r.Name == "person.phone.mobile" && (*r.GeneratorName != "phone" || *r.Template != "fr-FR;kind=mobile") {
+			t.Errorf("expected reseeding to update resource 'person.phone.mobile' but got %s", r.FullGeneratorName())
+		}
+	}
+	if len(resources) != before {
+		t.Errorf("expected reseeding not to duplicate resources, had %d but got %d", before, len(resources))
+	}
+}
@@ -27,22 +27,27 @@ func NewBasicUploader(db *sql.DB, table, typ string) *BasicUploader {
 }
 
 func (u *BasicUploader) Upload(data []*ParserRow) error {
-	props, err := models.LoadPropsAsMap(u.db, u.table, &u.typ, nil, func(p *models.Prop) string { return strings.ToLower(p.Value) })
+	// props are deduplicated per locale, as locales share words such as 'de'
+	key := func(localeId int64, value string) string {
+		return fmt.Sprintf("%d:%s", localeId, strings.ToLower(value))
+	}
+	props, err := models.LoadPropsAsMap(u.db, u.table, &u.typ, nil, func(p *models.Prop) string { return key(p.LocaleId, p.Value) })
 	if err != nil {
 		return fmt.Errorf("failed to load props, %s", err)
 	}
 	slog.Debug(fmt.Sprintf("Loaded %d props", len(props)))
 
-	propExists := func(value string) bool {
-		_, ok := props[strings.ToLower(value)]
+	propExists := func(row *ParserRow) bool {
+		_, ok := props[key(row.locale.Id, row.value)]
 		return ok
 	}
 
 	// timeStart := time.Now()
 	filteredRows := []*ParserRow{}
 	for _, row := range data {
-		if !propExists(row.value) {
+		if !propExists(row) {
 			filteredRows = append(filteredRows, row)
+			props[key(row.locale.Id, row.value)] = nil
 		}
 	}
 	// log.Printf("Filtered %d rows in %s -> %d left", len(data), time.Since(timeStart), len(filteredRows))
@@ -52,7 +57,7 @@ func (u *BasicUploader) Upload(data []*ParserRow) error {
 		return err
 	}
 
-	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s VALUES (NULL, ?, ?, ?)", u.table))
+	stmt, err := tx.Prepare(fmt.Sprintf("INSERT INTO %s (locale_id, type, value) VALUES (?, ?, ?)", u.table))
 	if err != nil {
 		return fmt.Errorf("failed to prepare insert query, %s", err)
 	}
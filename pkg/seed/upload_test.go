@@ -0,0 +1,47 @@
+package seed_test
+
+import (
+	"testing"
+
+	"github.com/welschmorgan/datagen/pkg/models"
+	"github.com/welschmorgan/datagen/pkg/seed"
+)
+
+func TestBasicUploaderDedupesPerLocale(t *testing.T) {
+	db, _ := seededDB(t)
+	locales, err := models.LoadLocales(db)
+	if err != nil {
+		t.Fatalf("failed to load locales, %s", err)
+	}
+	if len(locales) < 2 {
+		t.Fatalf("expected several seeded locales but got %v", locales)
+	}
+	uploader := seed.NewBasicUploader(db, "misc_prop", "test-word")
+	for _, loc := range locales[:2] {
+		rows := []*seed.ParserRow{}
+		for _, w := range []string{"de", "la", "De", loc.Name} {
+			rows = append(rows, seed.NewParserRow(loc, w, nil))
+		}
+		if err := uploader.Upload(rows); err != nil {
+			t.Fatalf("failed to upload %s words, %s", loc.Name, err)
+		}
+		// uploading again must not duplicate words
+		if err := uploader.Upload(rows); err != nil {
+			t.Fatalf("failed to upload %s words, %s", loc.Name, err)
+		}
+	}
+	typ := "test-word"
+	props, err := models.LoadProps(db, "misc_prop", &typ, nil)
+	if err != nil {
+		t.Fatalf("failed to load props, %s", err)
+	}
+	words := map[int64][]string{}
+	for _, p := range props {
+		words[p.LocaleId] = append(words[p.LocaleId], p.Value)
+	}
+	for _, loc := range locales[:2] {
+		if len(words[loc.Id]) != 3 {
+			t.Errorf("expected 'de', 'la' and '%s' for %s but got %v", loc.Name, loc.Name, words[loc.Id])
+		}
+	}
+}